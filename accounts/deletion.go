@@ -0,0 +1,108 @@
+// backend/accounts/deletion.go
+package accounts
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SystemActorID 由系統排程執行時的稽核記錄操作者ID
+const SystemActorID = 0
+
+// ErrAlreadyDeleted 帳號資料已清除
+var ErrAlreadyDeleted = errors.New("account already deleted")
+
+// RequestDeletion 申請刪除帳號，寬限期結束後由排程清除資料
+func RequestDeletion(ctx context.Context, store storage.Storage, actorID int, user models.User, at time.Time) (*models.User, error) {
+	if user.DeletedAt != nil {
+		return nil, ErrAlreadyDeleted
+	}
+	if user.DeletionRequestedAt != nil {
+		return &user, nil
+	}
+
+	user.DeletionRequestedAt = &at
+	return updateWithAudit(ctx, store, actorID, user, models.AuditAccountDeletionRequested, at)
+}
+
+// CancelDeletion 在寬限期內取消刪除帳號的申請
+func CancelDeletion(ctx context.Context, store storage.Storage, actorID int, user models.User, at time.Time) (*models.User, error) {
+	if user.DeletedAt != nil {
+		return nil, ErrAlreadyDeleted
+	}
+	if user.DeletionRequestedAt == nil {
+		return &user, nil
+	}
+
+	user.DeletionRequestedAt = nil
+	return updateWithAudit(ctx, store, actorID, user, models.AuditAccountDeletionCancelled, at)
+}
+
+// Purge 立即清除用戶資料並匿名化帳號
+func Purge(ctx context.Context, store storage.Storage, actorID int, user models.User, at time.Time) error {
+	if user.DeletedAt != nil {
+		return ErrAlreadyDeleted
+	}
+	return store.RunInTx(ctx, func(tx storage.Storage) error {
+		if err := tx.PurgeUser(ctx, user.OrgID, user.ID, at); err != nil {
+			return err
+		}
+		return audit(ctx, tx, actorID, user, models.AuditAccountPurged, at)
+	})
+}
+
+// PurgeDue 清除所有寬限期已結束的帳號，返回清除的數量
+// 單一帳號清除失敗時記錄到日誌並繼續處理其他帳號，全部處理完後返回失敗的數量。
+func PurgeDue(ctx context.Context, store storage.Storage, now time.Time) (int, error) {
+	users, err := store.GetUsersDueForDeletion(ctx, now.Add(-models.AccountDeletionGracePeriod))
+	if err != nil {
+		return 0, err
+	}
+	purged, failed := 0, 0
+	for _, user := range users {
+		if err := Purge(ctx, store, SystemActorID, user, now); err != nil {
+			log.Printf("purge account %d in organization %d: %v", user.ID, user.OrgID, err)
+			failed++
+			continue
+		}
+		purged++
+	}
+	if failed > 0 {
+		return purged, fmt.Errorf("%d of %d accounts could not be purged", failed, len(users))
+	}
+	return purged, nil
+}
+
+// updateWithAudit 在同一交易中更新帳號並記錄稽核，任一步失敗時一併回滾
+func updateWithAudit(ctx context.Context, store storage.Storage, actorID int, user models.User, action string, at time.Time) (*models.User, error) {
+	var updated *models.User
+	err := store.RunInTx(ctx, func(tx storage.Storage) error {
+		var err error
+		updated, err = tx.UpdateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		return audit(ctx, tx, actorID, user, action, at)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// audit 記錄帳號刪除相關的操作
+func audit(ctx context.Context, store storage.Storage, actorID int, user models.User, action string, at time.Time) error {
+	_, err := store.CreateAuditEntry(ctx, models.AuditEntry{
+		OrgID:        user.OrgID,
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: user.ID,
+		CreatedAt:    at,
+	})
+	return err
+}
@@ -0,0 +1,197 @@
+// backend/achievements/engine.go
+package achievements
+
+import (
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"time"
+)
+
+// Rule 成就規則：符合 Check 條件即解鎖對應成就
+type Rule struct {
+	Achievement models.Achievement
+	Check       func(c *Context) (bool, error)
+}
+
+// Context 規則評估時的用戶資料，按需從儲存載入並快取
+type Context struct {
+	OrgID  int
+	UserID int
+	Record models.StrokeRecord // 觸發評估的筆畫記錄
+
+	ctx      context.Context
+	store    storage.Storage
+	stats    []models.StrokeStat
+	progress models.UserProgress
+	streak   *models.Streak
+}
+
+// StrokeStats 返回用戶每一筆畫的練習彙總
+func (c *Context) StrokeStats() ([]models.StrokeStat, error) {
+	if c.stats == nil {
+		stats, err := c.store.GetStrokeStats(c.ctx, c.OrgID, []int{c.UserID})
+		if err != nil {
+			return nil, err
+		}
+		c.stats = stats
+	}
+	return c.stats, nil
+}
+
+// Attempts 返回用戶累積練習的筆畫數
+func (c *Context) Attempts() (int, error) {
+	progress, err := c.Progress()
+	if err != nil {
+		return 0, err
+	}
+	attempts := 0
+	for _, charProgress := range progress {
+		attempts += charProgress.Attempts
+	}
+	return attempts, nil
+}
+
+// Progress 返回用戶進度
+func (c *Context) Progress() (models.UserProgress, error) {
+	if c.progress == nil {
+		progress, err := c.store.GetUserProgress(c.ctx, c.OrgID, c.UserID)
+		if err != nil {
+			return nil, err
+		}
+		c.progress = progress
+	}
+	return c.progress, nil
+}
+
+// Streak 返回用戶連續練習記錄
+func (c *Context) Streak() (models.Streak, error) {
+	if c.streak == nil {
+		streak, err := c.store.GetStreak(c.ctx, c.OrgID, c.UserID)
+		if err != nil {
+			return models.Streak{}, err
+		}
+		c.streak = &streak
+	}
+	return *c.streak, nil
+}
+
+// Character 返回字元詳情
+func (c *Context) Character(id int) (*models.Character, error) {
+	return c.store.GetCharacterByID(c.ctx, c.OrgID, id)
+}
+
+// Decks 返回所有字卡組
+func (c *Context) Decks() ([]models.Deck, error) {
+	return c.store.GetDecks(c.ctx, c.OrgID)
+}
+
+// Engine 成就引擎，在每次練習後評估規則
+type Engine struct {
+	store storage.Storage
+	rules []Rule
+}
+
+// NewEngine 創建一個新的成就引擎
+func NewEngine(store storage.Storage, rules []Rule) *Engine {
+	return &Engine{
+		store: store,
+		rules: rules,
+	}
+}
+
+// WithStore 返回使用指定儲存的引擎副本，用於在交易中評估成就
+func (e *Engine) WithStore(store storage.Storage) *Engine {
+	return &Engine{
+		store: store,
+		rules: e.rules,
+	}
+}
+
+// Achievements 返回所有成就定義，名稱與說明依上下文的語系翻譯
+func (e *Engine) Achievements(ctx context.Context) []models.Achievement {
+	achievements := make([]models.Achievement, len(e.rules))
+	for i, rule := range e.rules {
+		achievements[i] = localize(ctx, rule.Achievement)
+	}
+	return achievements
+}
+
+// localize 以訊息目錄中的 achievement.<id>.name 與 achievement.<id>.description 翻譯成就，
+// 目錄中沒有時保留規則中的原文
+func localize(ctx context.Context, achievement models.Achievement) models.Achievement {
+	localizer := i18n.FromContext(ctx)
+	if name, ok := localizer.Lookup("achievement." + achievement.ID + ".name"); ok {
+		achievement.Name = name
+	}
+	if description, ok := localizer.Lookup("achievement." + achievement.ID + ".description"); ok {
+		achievement.Description = description
+	}
+	return achievement
+}
+
+// Evaluate 在用戶產生新的筆畫記錄後評估所有尚未解鎖的成就，返回本次新解鎖的成就
+func (e *Engine) Evaluate(ctx context.Context, orgID, userID int, record models.StrokeRecord) ([]models.UserAchievement, error) {
+	achievements, err := e.store.GetUserAchievements(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make(map[string]bool)
+	for _, achievement := range achievements {
+		unlocked[achievement.AchievementID] = true
+	}
+
+	c := &Context{OrgID: orgID, UserID: userID, Record: record, ctx: ctx, store: e.store}
+	now := time.Now()
+
+	var newlyUnlocked []models.UserAchievement
+	for _, rule := range e.rules {
+		if unlocked[rule.Achievement.ID] {
+			continue
+		}
+		met, err := rule.Check(c)
+		if err != nil {
+			return newlyUnlocked, err
+		}
+		if !met {
+			continue
+		}
+
+		ok, err := e.store.UnlockAchievement(ctx, orgID, userID, rule.Achievement.ID, now)
+		if err != nil {
+			return newlyUnlocked, err
+		}
+		if ok {
+			newlyUnlocked = append(newlyUnlocked, models.UserAchievement{
+				UserID:        userID,
+				AchievementID: rule.Achievement.ID,
+				UnlockedAt:    now,
+			})
+		}
+	}
+
+	return newlyUnlocked, nil
+}
+
+// Statuses 返回用戶所有成就的解鎖狀態，名稱與說明依上下文的語系翻譯
+func (e *Engine) Statuses(ctx context.Context, orgID, userID int) ([]models.AchievementStatus, error) {
+	achievements, err := e.store.GetUserAchievements(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlockedAt := make(map[string]time.Time)
+	for _, achievement := range achievements {
+		unlockedAt[achievement.AchievementID] = achievement.UnlockedAt
+	}
+
+	statuses := make([]models.AchievementStatus, len(e.rules))
+	for i, rule := range e.rules {
+		statuses[i] = models.AchievementStatus{Achievement: localize(ctx, rule.Achievement)}
+		if at, ok := unlockedAt[rule.Achievement.ID]; ok {
+			statuses[i].Unlocked = true
+			statuses[i].UnlockedAt = &at
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,134 @@
+// backend/achievements/rules.go
+package achievements
+
+import (
+	"backend/models"
+	"backend/storage"
+	"errors"
+)
+
+const (
+	// PerfectScore 筆畫得分達到此值視為完美
+	PerfectScore = 0.95
+	// StreakDays 連續練習成就所需天數
+	StreakDays = 7
+	// StrokeMilestone 練習量成就所需筆畫數
+	StrokeMilestone = 100
+)
+
+// DefaultRules 預設的成就規則
+var DefaultRules = []Rule{
+	{
+		Achievement: models.Achievement{
+			ID:          "first_perfect_character",
+			Name:        "完美一字",
+			Description: "一個字的每一筆都寫出完美得分",
+		},
+		Check: func(c *Context) (bool, error) {
+			return perfectCharacter(c, c.Record.CharacterID)
+		},
+	},
+	{
+		Achievement: models.Achievement{
+			ID:          "streak_7_days",
+			Name:        "持之以恆",
+			Description: "連續練習 7 天",
+		},
+		Check: func(c *Context) (bool, error) {
+			streak, err := c.Streak()
+			return err == nil && streak.Longest >= StreakDays, err
+		},
+	},
+	{
+		Achievement: models.Achievement{
+			ID:          "deck_mastered",
+			Name:        "字卡大師",
+			Description: "熟練一整組字卡中的所有字",
+		},
+		Check: func(c *Context) (bool, error) {
+			return deckMastered(c)
+		},
+	},
+	{
+		Achievement: models.Achievement{
+			ID:          "strokes_100",
+			Name:        "百筆練習",
+			Description: "累積練習 100 筆",
+		},
+		Check: func(c *Context) (bool, error) {
+			attempts, err := c.Attempts()
+			return err == nil && attempts >= StrokeMilestone, err
+		},
+	},
+}
+
+// perfectCharacter 檢查字元的每一筆是否都曾達到完美得分
+func perfectCharacter(c *Context, characterID int) (bool, error) {
+	character, err := c.Character(characterID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(character.StrokeData) == 0 {
+		return false, nil
+	}
+
+	stats, err := c.StrokeStats()
+	if err != nil {
+		return false, err
+	}
+	perfect := make(map[int]bool)
+	for _, stat := range stats {
+		if stat.CharacterID == characterID && stat.BestScore >= PerfectScore {
+			perfect[stat.StrokeIndex] = true
+		}
+	}
+
+	for i := range character.StrokeData {
+		if !perfect[i] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// deckMastered 檢查包含本次練習字元的字卡組是否已全部熟練
+func deckMastered(c *Context) (bool, error) {
+	progress, err := c.Progress()
+	if err != nil {
+		return false, err
+	}
+	decks, err := c.Decks()
+	if err != nil {
+		return false, err
+	}
+	for _, deck := range decks {
+		if !containsCharacter(deck, c.Record.CharacterID) {
+			continue
+		}
+
+		mastered := true
+		for _, characterID := range deck.CharacterIDs {
+			if progress[characterID].Mastery < models.MasteryThreshold {
+				mastered = false
+				break
+			}
+		}
+		if mastered {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsCharacter 檢查字卡組是否包含字元
+func containsCharacter(deck models.Deck, characterID int) bool {
+	for _, id := range deck.CharacterIDs {
+		if id == characterID {
+			return true
+		}
+	}
+	return false
+}
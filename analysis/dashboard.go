@@ -0,0 +1,179 @@
+// backend/analysis/dashboard.go
+package analysis
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// MaxFailedStrokes 儀表板列出最常失敗筆畫的數量上限
+const MaxFailedStrokes = 5
+
+// ClassDashboard 以筆畫彙總計算班級的字元平均、最常失敗筆畫、未練習學生與作業完成率
+func ClassDashboard(ctx context.Context, store storage.Storage, classroom *models.Classroom, now time.Time, inactiveSince time.Time) (*models.ClassDashboard, error) {
+	members, err := store.GetClassMembers(ctx, classroom.OrgID, classroom.ID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]int, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+
+	type characterTotals struct {
+		students map[int]bool
+		attempts int
+		scoreSum float64
+	}
+	characters := make(map[int]*characterTotals)
+	failedStrokes := make(map[[2]int]*models.FailedStrokeStats)
+	lastPracticed := make(map[int]time.Time)
+
+	stats, err := store.GetStrokeStats(ctx, classroom.OrgID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		totals, exists := characters[stat.CharacterID]
+		if !exists {
+			totals = &characterTotals{students: make(map[int]bool)}
+			characters[stat.CharacterID] = totals
+		}
+		totals.students[stat.UserID] = true
+		totals.attempts += stat.Attempts
+		totals.scoreSum += stat.ScoreSum
+
+		key := [2]int{stat.CharacterID, stat.StrokeIndex}
+		failed, exists := failedStrokes[key]
+		if !exists {
+			failed = &models.FailedStrokeStats{CharacterID: stat.CharacterID, StrokeIndex: stat.StrokeIndex}
+			failedStrokes[key] = failed
+		}
+		failed.Attempts += stat.Attempts
+		failed.Failures += stat.Failures
+
+		if stat.LastPracticedAt.After(lastPracticed[stat.UserID]) {
+			lastPracticed[stat.UserID] = stat.LastPracticedAt
+		}
+	}
+
+	dashboard := models.ClassDashboard{
+		ClassID:           classroom.ID,
+		Students:          len(members),
+		Characters:        []models.CharacterClassStats{},
+		MostFailedStrokes: []models.FailedStrokeStats{},
+		InactiveStudents:  []models.InactiveStudent{},
+		Assignments:       []models.AssignmentCompletion{},
+	}
+
+	// 每個字元的班級平均
+	previews, err := store.GetCharacters(ctx, classroom.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string)
+	for _, character := range previews {
+		names[character.ID] = character.Name
+	}
+	for characterID, totals := range characters {
+		dashboard.Characters = append(dashboard.Characters, models.CharacterClassStats{
+			CharacterID: characterID,
+			Name:        names[characterID],
+			Students:    len(totals.students),
+			Attempts:    totals.attempts,
+			AvgScore:    totals.scoreSum / float64(totals.attempts),
+		})
+	}
+	sort.Slice(dashboard.Characters, func(i, j int) bool {
+		return dashboard.Characters[i].CharacterID < dashboard.Characters[j].CharacterID
+	})
+
+	// 失敗次數最多的筆畫
+	for _, failed := range failedStrokes {
+		if failed.Failures == 0 {
+			continue
+		}
+		failed.FailureRate = float64(failed.Failures) / float64(failed.Attempts)
+		dashboard.MostFailedStrokes = append(dashboard.MostFailedStrokes, *failed)
+	}
+	sort.Slice(dashboard.MostFailedStrokes, func(i, j int) bool {
+		a, b := dashboard.MostFailedStrokes[i], dashboard.MostFailedStrokes[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.FailureRate != b.FailureRate {
+			return a.FailureRate > b.FailureRate
+		}
+		if a.CharacterID != b.CharacterID {
+			return a.CharacterID < b.CharacterID
+		}
+		return a.StrokeIndex < b.StrokeIndex
+	})
+	if len(dashboard.MostFailedStrokes) > MaxFailedStrokes {
+		dashboard.MostFailedStrokes = dashboard.MostFailedStrokes[:MaxFailedStrokes]
+	}
+
+	// 近期沒有練習的學生
+	for _, member := range members {
+		last, practiced := lastPracticed[member.UserID]
+		if practiced && !last.Before(inactiveSince) {
+			continue
+		}
+
+		inactive := models.InactiveStudent{UserID: member.UserID}
+		user, err := store.GetUserByID(ctx, classroom.OrgID, member.UserID)
+		switch {
+		case err == nil:
+			inactive.Username = user.Username
+		case !errors.Is(err, storage.ErrNotFound):
+			return nil, err
+		}
+		if practiced {
+			inactive.LastPracticedAt = &last
+		}
+		dashboard.InactiveStudents = append(dashboard.InactiveStudents, inactive)
+	}
+
+	// 作業完成率
+	assignments, err := store.GetAssignmentsByClassID(ctx, classroom.OrgID, classroom.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) > 0 {
+		progress := make(map[int]models.UserProgress, len(members))
+		for _, member := range members {
+			progress[member.UserID], err = store.GetUserProgress(ctx, classroom.OrgID, member.UserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, assignment := range assignments {
+			completion := models.AssignmentCompletion{
+				AssignmentID: assignment.ID,
+				Title:        assignment.Title,
+				DueAt:        assignment.DueAt,
+				Total:        len(members),
+			}
+			dueProgress, err := store.GetAssignmentDueProgress(ctx, classroom.OrgID, assignment.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				if assignment.StatusFor(member.UserID, progress[member.UserID], dueProgress, now).Status == models.AssignmentCompleted {
+					completion.Completed++
+				}
+			}
+			if completion.Total > 0 {
+				completion.CompletionRate = float64(completion.Completed) / float64(completion.Total)
+			}
+			dashboard.Assignments = append(dashboard.Assignments, completion)
+		}
+	}
+
+	return &dashboard, nil
+}
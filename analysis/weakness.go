@@ -0,0 +1,126 @@
+// backend/analysis/weakness.go
+package analysis
+
+import (
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"sort"
+)
+
+// MaxDrillCharacters 每種弱項筆畫建議練習的字元數上限
+const MaxDrillCharacters = 3
+
+// StrokeTypeWeaknesses 依參考筆畫類型彙總用戶在所有字元上的得分，由弱到強排序
+func StrokeTypeWeaknesses(ctx context.Context, store storage.Storage, orgID, userID int) ([]models.StrokeTypeWeakness, error) {
+	characters := make(map[int]*models.Character)
+	characterFor := func(id int) (*models.Character, error) {
+		character, loaded := characters[id]
+		if !loaded {
+			var err error
+			character, err = store.GetCharacterByID(ctx, orgID, id)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return nil, err
+			}
+			characters[id] = character
+		}
+		return character, nil
+	}
+
+	// 以每一筆畫的練習彙總計算，不需讀取完整的筆畫記錄
+	stats, err := store.GetStrokeStats(ctx, orgID, []int{userID})
+	if err != nil {
+		return nil, err
+	}
+	attempts := make(map[models.StrokeType]int)
+	scoreSums := make(map[models.StrokeType]float64)
+	for _, stat := range stats {
+		character, err := characterFor(stat.CharacterID)
+		if err != nil {
+			return nil, err
+		}
+		if character == nil || stat.StrokeIndex >= len(character.StrokeData) {
+			continue
+		}
+
+		strokeType := character.StrokeData[stat.StrokeIndex].Type
+		attempts[strokeType] += stat.Attempts
+		scoreSums[strokeType] += stat.ScoreSum
+	}
+
+	progress, err := store.GetUserProgress(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	weaknesses := []models.StrokeTypeWeakness{}
+	for _, strokeType := range models.StrokeTypes {
+		if attempts[strokeType] == 0 {
+			continue
+		}
+
+		avgScore := scoreSums[strokeType] / float64(attempts[strokeType])
+		weakness := models.StrokeTypeWeakness{
+			Type:              strokeType,
+			Attempts:          attempts[strokeType],
+			AvgScore:          avgScore,
+			Weak:              avgScore < models.WeakStrokeScore,
+			Message:           i18n.T(ctx, "Your %s strokes average %.2f", strokeTypeName(ctx, strokeType), avgScore),
+			DrillCharacterIDs: []int{},
+		}
+		if weakness.Weak {
+			weakness.DrillCharacterIDs, err = drillCharacters(ctx, store, orgID, characterFor, progress, strokeType)
+			if err != nil {
+				return nil, err
+			}
+		}
+		weaknesses = append(weaknesses, weakness)
+	}
+
+	sort.SliceStable(weaknesses, func(i, j int) bool {
+		return weaknesses[i].AvgScore < weaknesses[j].AvgScore
+	})
+	return weaknesses, nil
+}
+
+// strokeTypeName 返回筆畫類型在上下文語系中的名稱，訊息目錄以 stroke.<類型> 為鍵
+func strokeTypeName(ctx context.Context, strokeType models.StrokeType) string {
+	if name, ok := i18n.FromContext(ctx).Lookup("stroke." + string(strokeType)); ok {
+		return name
+	}
+	return string(strokeType)
+}
+
+// drillCharacters 挑選含有該筆畫類型且尚未熟練的字元作為針對性練習
+func drillCharacters(ctx context.Context, store storage.Storage, orgID int, characterFor func(int) (*models.Character, error),
+	progress models.UserProgress, strokeType models.StrokeType) ([]int, error) {
+	previews, err := store.GetCharacters(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	drills := []int{}
+	for _, preview := range previews {
+		if progress[preview.ID].Mastery >= models.MasteryThreshold {
+			continue
+		}
+
+		character, err := characterFor(preview.ID)
+		if err != nil {
+			return nil, err
+		}
+		if character == nil {
+			continue
+		}
+		for _, stroke := range character.StrokeData {
+			if stroke.Type == strokeType {
+				drills = append(drills, preview.ID)
+				break
+			}
+		}
+		if len(drills) == MaxDrillCharacters {
+			break
+		}
+	}
+	return drills, nil
+}
@@ -0,0 +1,94 @@
+// backend/apierror/apierror.go
+
+// Package apierror 定義 API 統一的錯誤回應格式
+//
+// 所有錯誤回應都是 JSON 物件：
+//
+//	{"code": "validation_failed", "message": "...", "details": [{"field": "name", "message": "..."}], "requestId": "..."}
+//
+// 前端以 code 判斷錯誤類型並自行翻譯，message 僅供開發與除錯參考。
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Code 機器可讀的錯誤代碼
+type Code string
+
+// 錯誤代碼，新增代碼時前端需同步更新翻譯
+const (
+	CodeInvalidRequest   Code = "invalid_request"   // 請求格式錯誤或路徑參數無效
+	CodeValidationFailed Code = "validation_failed" // 欄位驗證失敗，details 列出各欄位的問題
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeMethodNotAllowed Code = "method_not_allowed"
+	CodeConflict         Code = "conflict"
+	CodePayloadTooLarge  Code = "payload_too_large"
+	CodeInternal         Code = "internal_error"
+)
+
+// FieldError 單一欄位的驗證錯誤
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error 錯誤回應的內容
+type Error struct {
+	Code      Code         `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// CodeForStatus 返回 HTTP 狀態碼對應的預設錯誤代碼
+func CodeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	}
+	return CodeInternal
+}
+
+// Write 以統一格式回應錯誤，並附上請求的追蹤ID
+func Write(w http.ResponseWriter, r *http.Request, status int, code Code, message string, details ...FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Error{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: RequestIDFromContext(r.Context()),
+	})
+}
+
+// 創建上下文鍵類型
+type contextKey struct{}
+
+// WithRequestID 將請求的追蹤ID加入上下文
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// RequestIDFromContext 從上下文中獲取請求的追蹤ID，未設定時返回空字串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,294 @@
+// backend/backup/archive.go
+
+// Package backup 將任一 storage.Storage 後端的完整資料備份為可攜的 zip 壓縮檔，並可還原至另一個後端
+package backup
+
+import (
+	"archive/zip"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// FormatVersion 備份檔格式版本，格式不相容時遞增
+const FormatVersion = 1
+
+// recordPageSize 逐頁讀取筆畫記錄時每頁的筆數
+const recordPageSize = 500
+
+// 備份檔中的檔案名稱
+const (
+	manifestFile      = "manifest.json"
+	organizationsFile = "organizations.json"
+	usersFile         = "users.json"
+	charactersFile    = "characters.json"
+	decksFile         = "decks.json"
+	goalsFile         = "goals.json"
+	progressFile      = "progress.json"
+	achievementsFile  = "achievements.json"
+	classroomsFile    = "classrooms.json"
+	membersFile       = "class_members.json"
+	assignmentsFile   = "assignments.json"
+	guardianLinksFile = "guardian_links.json"
+	auditLogFile      = "audit_log.json"
+	strokeRecordsFile = "stroke_records.jsonl" // 每行一筆，依用戶與記錄ID排序
+)
+
+// Manifest 備份檔的版本與各類資料筆數
+type Manifest struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Counts    map[string]int `json:"counts"`
+}
+
+// User 備份中的用戶，包含 JSON 回應中隱藏的密碼
+type User struct {
+	models.User
+	Password string `json:"password"`
+}
+
+// Write 將儲存中所有組織的資料寫入 zip 壓縮檔
+// 內建字元與字卡組由各後端自行提供，不包含在備份中；筆畫記錄逐頁讀取並直接寫出。
+func Write(ctx context.Context, store storage.Storage, w io.Writer) (*Manifest, error) {
+	d, err := collect(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := zip.NewWriter(w)
+	manifest := &Manifest{Version: FormatVersion, CreatedAt: time.Now(), Counts: map[string]int{}}
+
+	files := []struct {
+		name  string
+		value interface{}
+		count int
+	}{
+		{organizationsFile, d.organizations, len(d.organizations)},
+		{usersFile, d.users, len(d.users)},
+		{charactersFile, d.characters, len(d.characters)},
+		{decksFile, d.decks, len(d.decks)},
+		{goalsFile, d.goals, len(d.goals)},
+		{progressFile, d.progress, len(d.progress)},
+		{achievementsFile, d.achievements, len(d.achievements)},
+		{classroomsFile, d.classrooms, len(d.classrooms)},
+		{membersFile, d.members, len(d.members)},
+		{assignmentsFile, d.assignments, len(d.assignments)},
+		{guardianLinksFile, d.guardianLinks, len(d.guardianLinks)},
+		{auditLogFile, d.auditEntries, len(d.auditEntries)},
+	}
+	for _, file := range files {
+		if err := writeJSON(archive, file.name, file.value); err != nil {
+			return nil, err
+		}
+		manifest.Counts[file.name] = file.count
+	}
+
+	records, err := writeStrokeRecords(ctx, store, archive, d.users)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Counts[strokeRecordsFile] = records
+
+	if err := writeJSON(archive, manifestFile, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, archive.Close()
+}
+
+// dataset 除筆畫記錄外的所有備份資料
+type dataset struct {
+	organizations []models.Organization
+	users         []User
+	characters    []models.Character
+	decks         []models.Deck
+	goals         []models.DailyGoal
+	progress      map[int]models.UserProgress // userID -> 進度，還原時用於核對重建結果
+	achievements  []models.UserAchievement
+	classrooms    []models.Classroom
+	members       []models.ClassMembership
+	assignments   []models.Assignment
+	guardianLinks []models.GuardianLink
+	auditEntries  []models.AuditEntry
+}
+
+// collect 逐一讀取各組織的資料
+func collect(ctx context.Context, store storage.Storage) (*dataset, error) {
+	orgs, err := store.GetOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dataset{
+		organizations: orgs,
+		users:         []User{},
+		characters:    []models.Character{},
+		decks:         []models.Deck{},
+		goals:         []models.DailyGoal{},
+		progress:      make(map[int]models.UserProgress),
+		achievements:  []models.UserAchievement{},
+		members:       []models.ClassMembership{},
+		assignments:   []models.Assignment{},
+		guardianLinks: []models.GuardianLink{},
+		auditEntries:  []models.AuditEntry{},
+	}
+	classrooms := make(map[int]models.Classroom)
+	for _, org := range orgs {
+		users, err := store.GetUsers(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if err := d.addUser(ctx, store, user, classrooms); err != nil {
+				return nil, err
+			}
+		}
+
+		characters, err := store.GetCharacters(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, preview := range characters {
+			if preview.OrgID != org.ID {
+				continue
+			}
+			character, err := store.GetCharacterByID(ctx, org.ID, preview.ID)
+			if err != nil {
+				return nil, err
+			}
+			d.characters = append(d.characters, *character)
+		}
+
+		decks, err := store.GetDecks(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, deck := range decks {
+			if deck.OrgID == org.ID {
+				d.decks = append(d.decks, deck)
+			}
+		}
+
+		entries, err := store.GetAuditEntries(ctx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		d.auditEntries = append(d.auditEntries, entries...)
+	}
+
+	// 班級只能經由任課老師取得，依ID排序後再讀取成員與作業
+	d.classrooms = make([]models.Classroom, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		d.classrooms = append(d.classrooms, classroom)
+	}
+	sort.Slice(d.classrooms, func(i, j int) bool { return d.classrooms[i].ID < d.classrooms[j].ID })
+	for _, classroom := range d.classrooms {
+		members, err := store.GetClassMembers(ctx, classroom.OrgID, classroom.ID)
+		if err != nil {
+			return nil, err
+		}
+		d.members = append(d.members, members...)
+
+		assignments, err := store.GetAssignmentsByClassID(ctx, classroom.OrgID, classroom.ID)
+		if err != nil {
+			return nil, err
+		}
+		d.assignments = append(d.assignments, assignments...)
+	}
+
+	return d, nil
+}
+
+// addUser 讀取用戶本身、學習設定、成就、任教班級與監護人連結
+func (d *dataset) addUser(ctx context.Context, store storage.Storage, user models.User, classrooms map[int]models.Classroom) error {
+	d.users = append(d.users, User{User: user, Password: user.Password})
+
+	goal, err := store.GetDailyGoal(ctx, user.OrgID, user.ID)
+	if err != nil {
+		return err
+	}
+	d.goals = append(d.goals, goal)
+
+	progress, err := store.GetUserProgress(ctx, user.OrgID, user.ID)
+	if err != nil {
+		return err
+	}
+	if len(progress) > 0 {
+		d.progress[user.ID] = progress
+	}
+
+	achievements, err := store.GetUserAchievements(ctx, user.OrgID, user.ID)
+	if err != nil {
+		return err
+	}
+	d.achievements = append(d.achievements, achievements...)
+
+	taught, err := store.GetClassroomsByTeacherID(ctx, user.OrgID, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, classroom := range taught {
+		classrooms[classroom.ID] = classroom
+	}
+
+	links, err := store.GetGuardianLinksByGuardianID(ctx, user.OrgID, user.ID)
+	if err != nil {
+		return err
+	}
+	d.guardianLinks = append(d.guardianLinks, links...)
+	return nil
+}
+
+// writeStrokeRecords 逐頁讀取每位用戶的筆畫記錄並以每行一筆寫出，返回寫出的筆數
+func writeStrokeRecords(ctx context.Context, store storage.Storage, archive *zip.Writer, users []User) (int, error) {
+	file, err := createFile(archive, strokeRecordsFile)
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(file)
+	count := 0
+	for _, user := range users {
+		query := models.StrokeRecordQuery{UserID: user.ID, Limit: recordPageSize}
+		for {
+			page, err := store.QueryStrokeRecords(ctx, user.OrgID, query)
+			if err != nil {
+				return count, err
+			}
+			for _, record := range page.Records {
+				if err := encoder.Encode(record); err != nil {
+					return count, err
+				}
+				count++
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+	return count, nil
+}
+
+// createFile 在壓縮檔中建立以當前時間標記的檔案
+func createFile(archive *zip.Writer, name string) (io.Writer, error) {
+	return archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+}
+
+// writeJSON 將資料以 JSON 格式寫入壓縮檔
+func writeJSON(archive *zip.Writer, name string, value interface{}) error {
+	file, err := createFile(archive, name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
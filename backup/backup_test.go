@@ -0,0 +1,190 @@
+// backend/backup/backup_test.go
+package backup
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/storage/memory"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// populate 在預設資料之外寫入涵蓋每個備份檔的內容，返回有練習記錄的學生
+func populate(t *testing.T, store storage.Storage) *models.User {
+	t.Helper()
+	ctx := context.Background()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	org, err := store.CreateOrganization(ctx, models.Organization{Name: "School", Slug: "school"})
+	must(err)
+	teacher, err := store.CreateUser(ctx, models.User{Username: "teacher", Password: "pw", Role: models.RoleTeacher, OrgID: org.ID})
+	must(err)
+	student, err := store.CreateUser(ctx, models.User{Username: "student", Password: "pw", Role: models.RoleStudent, OrgID: org.ID})
+	must(err)
+
+	character, err := store.CreateCharacter(ctx, models.Character{
+		Name:       "人",
+		OrgID:      org.ID,
+		StrokeData: []models.Stroke{{Nodes: []models.Node{{X: 50, Y: 10}, {X: 10, Y: 90}}}, {Nodes: []models.Node{{X: 50, Y: 30}, {X: 90, Y: 90}}}},
+	})
+	must(err)
+	_, err = store.CreateDeck(ctx, models.Deck{Name: "基礎", CharacterIDs: []int{1, character.ID}, OrgID: org.ID})
+	must(err)
+	must(store.SetDailyGoal(ctx, models.DailyGoal{UserID: student.ID, TargetCharacters: 3, TargetMinutes: 10, Timezone: "Asia/Taipei"}))
+
+	// 兩天的練習，包含內建字元與自訂字元
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	practice := []struct {
+		characterID, strokeIndex int
+		score                    float64
+		at                       time.Time
+	}{
+		{1, 0, 0.6, start},
+		{1, 0, 0.9, start.Add(time.Minute)},
+		{character.ID, 0, 0.7, start.Add(2 * time.Minute)},
+		{character.ID, 1, 0.8, start.Add(24 * time.Hour)},
+		{1, 0, 1.0, start.Add(25 * time.Hour)},
+	}
+	for _, p := range practice {
+		record, err := store.CreateStrokeRecord(ctx, models.StrokeRecord{
+			UserID:      student.ID,
+			CharacterID: p.characterID,
+			StrokeIndex: p.strokeIndex,
+			Path:        []models.Node{{X: 0, Y: 0}, {X: 10, Y: 10}},
+			Score:       p.score,
+			CreatedAt:   p.at,
+		})
+		must(err)
+		must(storage.ApplyStrokeRecord(ctx, store, org.ID, *record))
+	}
+	_, err = store.UnlockAchievement(ctx, org.ID, student.ID, "first_stroke", start)
+	must(err)
+
+	classroom, err := store.CreateClassroom(ctx, models.Classroom{OrgID: org.ID, Name: "1A", TeacherID: teacher.ID, EnrolmentCode: "ROUND1"})
+	must(err)
+	_, err = store.AddClassMember(ctx, classroom.ID, student.ID)
+	must(err)
+	_, err = store.CreateAssignment(ctx, models.Assignment{ClassID: classroom.ID, Title: "人", CharacterIDs: []int{character.ID}, DueAt: start.Add(48 * time.Hour), MasteryThreshold: 0.5})
+	must(err)
+	_, err = store.CreateGuardianLink(ctx, models.GuardianLink{GuardianID: teacher.ID, ChildID: student.ID, Status: models.GuardianLinkApproved})
+	must(err)
+	_, err = store.CreateAuditEntry(ctx, models.AuditEntry{OrgID: org.ID, ActorID: teacher.ID, Action: models.AuditAccountDeletionRequested, TargetUserID: student.ID, CreatedAt: start})
+	must(err)
+	return student
+}
+
+// mustWrite 備份儲存並返回備份內容與清單
+func mustWrite(t *testing.T, store storage.Storage) ([]byte, *Manifest) {
+	t.Helper()
+	var buf bytes.Buffer
+	manifest, err := Write(context.Background(), store, &buf)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.Bytes(), manifest
+}
+
+func TestWriteRestoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := memory.NewMemoryStorage()
+	student := populate(t, source)
+	data, manifest := mustWrite(t, source)
+
+	target := memory.NewMemoryStorage()
+	report, err := Restore(ctx, target, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(report.Warnings) > 0 {
+		t.Fatalf("Restore warnings: %v", report.Warnings)
+	}
+
+	// 預設組織與管理員對應到目標中已有的資料，其餘資料一律新建
+	reported := map[string]int{
+		usersFile:         report.Users,
+		charactersFile:    report.Characters,
+		decksFile:         report.Decks,
+		classroomsFile:    report.Classrooms,
+		assignmentsFile:   report.Assignments,
+		guardianLinksFile: report.GuardianLinks,
+		achievementsFile:  report.Achievements,
+		auditLogFile:      report.AuditEntries,
+		strokeRecordsFile: report.StrokeRecords,
+	}
+	for file, got := range reported {
+		if want := manifest.Counts[file]; got != want {
+			t.Errorf("restored %d entries of %s, backup has %d", got, file, want)
+		}
+	}
+
+	// 再次備份目標儲存，每個檔案的筆數都應與原備份相同
+	_, restored := mustWrite(t, target)
+	for file, want := range manifest.Counts {
+		if got := restored.Counts[file]; got != want {
+			t.Errorf("%s: %d entries after restore, want %d", file, got, want)
+		}
+	}
+
+	// 進度與連續天數依筆畫記錄重建
+	org, err := target.GetOrganizationBySlug(ctx, "school")
+	if err != nil {
+		t.Fatalf("GetOrganizationBySlug: %v", err)
+	}
+	user, err := target.GetUserByUsername(ctx, org.ID, student.Username)
+	if err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	want, err := source.GetUserProgress(ctx, student.OrgID, student.ID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	got, err := target.GetUserProgress(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("restored progress for %d characters, want %d", len(got), len(want))
+	}
+	for characterID, expected := range want {
+		// 自訂字元在兩個儲存中都接在相同的內建字元之後建立，ID 相同
+		progress := got[characterID]
+		if progress.Attempts != expected.Attempts || progress.AvgScore != expected.AvgScore ||
+			progress.RecentScore != expected.RecentScore || !progress.LastPracticedAt.Equal(expected.LastPracticedAt) {
+			t.Errorf("character %d progress = %+v, want %+v", characterID, progress, expected)
+		}
+	}
+	if streak, err := target.GetStreak(ctx, org.ID, user.ID); err != nil || streak.Current != 2 {
+		t.Errorf("restored streak = %+v, %v; want 2 days", streak, err)
+	}
+}
+
+func TestRestoreIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	source := memory.NewMemoryStorage()
+	populate(t, source)
+	data, _ := mustWrite(t, source)
+
+	target := memory.NewMemoryStorage()
+	if _, err := Restore(ctx, target, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	_, before := mustWrite(t, target)
+
+	// 再次還原時用戶已有筆畫記錄，整個還原失敗且不留下任何部分寫入
+	if _, err := Restore(ctx, target, bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Fatal("restoring the same backup twice succeeded")
+	}
+	_, after := mustWrite(t, target)
+	for file, want := range before.Counts {
+		if got := after.Counts[file]; got != want {
+			t.Errorf("%s: %d entries after the failed restore, want %d", file, got, want)
+		}
+	}
+}
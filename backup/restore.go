@@ -0,0 +1,486 @@
+// backend/backup/restore.go
+package backup
+
+import (
+	"archive/zip"
+	"backend/models"
+	"backend/storage"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxRecordLine 筆畫記錄檔中單行的長度上限
+const maxRecordLine = 16 << 20
+
+// Report 還原結果：各類資料的筆數與需要注意的差異
+type Report struct {
+	Organizations int      `json:"organizations"`
+	Users         int      `json:"users"`
+	Characters    int      `json:"characters"`
+	Decks         int      `json:"decks"`
+	Classrooms    int      `json:"classrooms"`
+	Assignments   int      `json:"assignments"`
+	GuardianLinks int      `json:"guardianLinks"`
+	Achievements  int      `json:"achievements"`
+	AuditEntries  int      `json:"auditEntries"`
+	StrokeRecords int      `json:"strokeRecords"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// restorer 還原時的ID對照：備份中的ID -> 目標儲存中的ID
+type restorer struct {
+	ctx        context.Context
+	store      storage.Storage
+	archive    *zip.Reader
+	report     *Report
+	orgs       map[int]int
+	users      map[int]int
+	userOrgs   map[int]int // 目標中的用戶ID -> 組織ID
+	characters map[int]int
+	classrooms map[int]int
+}
+
+// Restore 將備份檔載入目標儲存
+// 組織以代稱、用戶以組織內的用戶名對應到目標中已有的資料，其餘資料一律新建並重新編號。
+// 進度、每日統計、連續天數與排行榜彙總依筆畫記錄的時間順序重建，並與備份中的進度核對。
+// 目標中已對應的用戶不可已有筆畫記錄，以免重複還原。
+// 整個還原在單一交易中執行，任何一步失敗時目標儲存維持原狀，可修正問題後重新還原。
+func Restore(ctx context.Context, store storage.Storage, r io.ReaderAt, size int64) (*Report, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := readJSON(archive, manifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+
+	res := &restorer{
+		ctx:        ctx,
+		archive:    archive,
+		report:     &Report{},
+		orgs:       make(map[int]int),
+		users:      make(map[int]int),
+		userOrgs:   make(map[int]int),
+		characters: make(map[int]int),
+		classrooms: make(map[int]int),
+	}
+
+	// 作業在筆畫記錄之前還原，重建進度時才會保存作業到期時的進度
+	steps := []func() error{
+		res.restoreOrganizations,
+		res.restoreUsers,
+		res.restoreCharacters,
+		res.restoreDecks,
+		res.restoreGoals,
+		res.restoreClassrooms,
+		res.restoreStrokeRecords,
+		res.verifyProgress,
+		res.restoreAchievements,
+		res.restoreGuardianLinks,
+		res.restoreAuditLog,
+	}
+	err = store.RunInTx(ctx, func(tx storage.Storage) error {
+		res.store = tx
+		for _, step := range steps {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.report, nil
+}
+
+// restoreOrganizations 以代稱對應既有組織，不存在時建立
+func (res *restorer) restoreOrganizations() error {
+	var orgs []models.Organization
+	if err := readJSON(res.archive, organizationsFile, &orgs); err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		existing, err := res.store.GetOrganizationBySlug(res.ctx, org.Slug)
+		if err == nil {
+			res.orgs[org.ID] = existing.ID
+			continue
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		created, err := res.store.CreateOrganization(res.ctx, models.Organization{Name: org.Name, Slug: org.Slug, CreatedAt: org.CreatedAt})
+		if err != nil {
+			return fmt.Errorf("organization %q: %w", org.Slug, err)
+		}
+		res.orgs[org.ID] = created.ID
+		res.report.Organizations++
+	}
+	return nil
+}
+
+// restoreUsers 以用戶名對應組織內的既有用戶並覆寫其資料，不存在時建立
+func (res *restorer) restoreUsers() error {
+	var users []User
+	if err := readJSON(res.archive, usersFile, &users); err != nil {
+		return err
+	}
+
+	for _, backupUser := range users {
+		user := backupUser.User
+		user.Password = backupUser.Password
+		orgID, ok := res.orgs[user.OrgID]
+		if !ok {
+			return fmt.Errorf("user %q: organization %d missing from backup", user.Username, user.OrgID)
+		}
+		user.OrgID = orgID
+
+		existing, err := res.store.GetUserByUsername(res.ctx, orgID, user.Username)
+		switch {
+		case err == nil:
+			records, err := res.store.GetStrokeRecordsByUserID(res.ctx, orgID, existing.ID)
+			if err != nil {
+				return err
+			}
+			if len(records) > 0 {
+				return fmt.Errorf("user %q already has stroke records in the target storage", user.Username)
+			}
+			user.ID = existing.ID
+			if _, err := res.store.UpdateUser(res.ctx, user); err != nil {
+				return fmt.Errorf("user %q: %w", user.Username, err)
+			}
+			res.users[backupUser.ID] = existing.ID
+			res.userOrgs[existing.ID] = orgID
+		case errors.Is(err, storage.ErrNotFound):
+			created, err := res.store.CreateUser(res.ctx, user)
+			if err != nil {
+				return fmt.Errorf("user %q: %w", user.Username, err)
+			}
+			res.users[backupUser.ID] = created.ID
+			res.userOrgs[created.ID] = orgID
+		default:
+			return err
+		}
+		res.report.Users++
+	}
+	return nil
+}
+
+// restoreCharacters 建立組織的自訂字元
+func (res *restorer) restoreCharacters() error {
+	var characters []models.Character
+	if err := readJSON(res.archive, charactersFile, &characters); err != nil {
+		return err
+	}
+
+	for _, character := range characters {
+		oldID := character.ID
+		orgID, ok := res.orgs[character.OrgID]
+		if !ok {
+			return fmt.Errorf("character %d: organization %d missing from backup", oldID, character.OrgID)
+		}
+		character.OrgID = orgID
+		created, err := res.store.CreateCharacter(res.ctx, character)
+		if err != nil {
+			return fmt.Errorf("character %d: %w", oldID, err)
+		}
+		res.characters[oldID] = created.ID
+		res.report.Characters++
+	}
+	return nil
+}
+
+// restoreDecks 建立組織的自訂字卡組
+func (res *restorer) restoreDecks() error {
+	var decks []models.Deck
+	if err := readJSON(res.archive, decksFile, &decks); err != nil {
+		return err
+	}
+
+	for _, deck := range decks {
+		orgID, ok := res.orgs[deck.OrgID]
+		if !ok {
+			return fmt.Errorf("deck %d: organization %d missing from backup", deck.ID, deck.OrgID)
+		}
+		deck.OrgID = orgID
+		deck.CharacterIDs = res.characterIDs(deck.CharacterIDs)
+		if _, err := res.store.CreateDeck(res.ctx, deck); err != nil {
+			return fmt.Errorf("deck %d: %w", deck.ID, err)
+		}
+		res.report.Decks++
+	}
+	return nil
+}
+
+// restoreGoals 在重建每日統計前還原每日目標，以正確的時區判斷練習日期
+func (res *restorer) restoreGoals() error {
+	var goals []models.DailyGoal
+	if err := readJSON(res.archive, goalsFile, &goals); err != nil {
+		return err
+	}
+
+	for _, goal := range goals {
+		userID, err := res.userID(goal.UserID)
+		if err != nil {
+			return err
+		}
+		goal.UserID = userID
+		if err := res.store.SetDailyGoal(res.ctx, goal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreStrokeRecords 逐行讀取並建立筆畫記錄，依記錄順序重建進度與每日統計
+func (res *restorer) restoreStrokeRecords() error {
+	file, err := res.archive.Open(strokeRecordsFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordLine)
+	for scanner.Scan() {
+		var record models.StrokeRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("%s line %d: %w", strokeRecordsFile, res.report.StrokeRecords+1, err)
+		}
+
+		userID, err := res.userID(record.UserID)
+		if err != nil {
+			return err
+		}
+		record.ID = 0
+		record.UserID = userID
+		record.CharacterID = res.characterID(record.CharacterID)
+
+		created, err := res.store.CreateStrokeRecord(res.ctx, record)
+		if err != nil {
+			return fmt.Errorf("stroke record for user %d: %w", record.UserID, err)
+		}
+		if err := storage.ApplyStrokeRecord(res.ctx, res.store, res.userOrgs[created.UserID], *created); err != nil {
+			return err
+		}
+		res.report.StrokeRecords++
+	}
+	return scanner.Err()
+}
+
+// verifyProgress 核對重建的練習次數與備份中的進度
+func (res *restorer) verifyProgress() error {
+	var progress map[int]models.UserProgress
+	if err := readJSON(res.archive, progressFile, &progress); err != nil {
+		return err
+	}
+
+	for oldUserID, userProgress := range progress {
+		userID, err := res.userID(oldUserID)
+		if err != nil {
+			return err
+		}
+		rebuilt, err := res.store.GetUserProgress(res.ctx, res.userOrgs[userID], userID)
+		if err != nil {
+			return err
+		}
+		for oldCharacterID, expected := range userProgress {
+			if got := rebuilt[res.characterID(oldCharacterID)]; got.Attempts != expected.Attempts {
+				res.report.Warnings = append(res.report.Warnings, fmt.Sprintf(
+					"user %d character %d: rebuilt %d attempts, backup has %d",
+					userID, oldCharacterID, got.Attempts, expected.Attempts))
+			}
+		}
+	}
+	return nil
+}
+
+// restoreAchievements 以原解鎖時間還原成就
+func (res *restorer) restoreAchievements() error {
+	var achievements []models.UserAchievement
+	if err := readJSON(res.archive, achievementsFile, &achievements); err != nil {
+		return err
+	}
+
+	for _, achievement := range achievements {
+		userID, err := res.userID(achievement.UserID)
+		if err != nil {
+			return err
+		}
+		unlocked, err := res.store.UnlockAchievement(res.ctx, res.userOrgs[userID], userID, achievement.AchievementID, achievement.UnlockedAt)
+		if err != nil {
+			return err
+		}
+		if unlocked {
+			res.report.Achievements++
+		}
+	}
+	return nil
+}
+
+// restoreClassrooms 建立班級、加入成員並建立作業
+func (res *restorer) restoreClassrooms() error {
+	var classrooms []models.Classroom
+	if err := readJSON(res.archive, classroomsFile, &classrooms); err != nil {
+		return err
+	}
+	for _, classroom := range classrooms {
+		oldID := classroom.ID
+		orgID, ok := res.orgs[classroom.OrgID]
+		if !ok {
+			return fmt.Errorf("classroom %d: organization %d missing from backup", oldID, classroom.OrgID)
+		}
+		teacherID, err := res.userID(classroom.TeacherID)
+		if err != nil {
+			return err
+		}
+		classroom.OrgID = orgID
+		classroom.TeacherID = teacherID
+		created, err := res.store.CreateClassroom(res.ctx, classroom)
+		if err != nil {
+			return fmt.Errorf("classroom %q: %w", classroom.Name, err)
+		}
+		res.classrooms[oldID] = created.ID
+		res.report.Classrooms++
+	}
+
+	var members []models.ClassMembership
+	if err := readJSON(res.archive, membersFile, &members); err != nil {
+		return err
+	}
+	for _, member := range members {
+		userID, err := res.userID(member.UserID)
+		if err != nil {
+			return err
+		}
+		if _, err := res.store.AddClassMember(res.ctx, res.classrooms[member.ClassID], userID); err != nil {
+			return fmt.Errorf("class member %d: %w", userID, err)
+		}
+	}
+
+	var assignments []models.Assignment
+	if err := readJSON(res.archive, assignmentsFile, &assignments); err != nil {
+		return err
+	}
+	for _, assignment := range assignments {
+		classID, ok := res.classrooms[assignment.ClassID]
+		if !ok {
+			return fmt.Errorf("assignment %d: classroom %d missing from backup", assignment.ID, assignment.ClassID)
+		}
+		assignment.ClassID = classID
+		assignment.CharacterIDs = res.characterIDs(assignment.CharacterIDs)
+		if _, err := res.store.CreateAssignment(res.ctx, assignment); err != nil {
+			return fmt.Errorf("assignment %q: %w", assignment.Title, err)
+		}
+		res.report.Assignments++
+	}
+	return nil
+}
+
+// restoreGuardianLinks 以原狀態還原監護人連結
+func (res *restorer) restoreGuardianLinks() error {
+	var links []models.GuardianLink
+	if err := readJSON(res.archive, guardianLinksFile, &links); err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		guardianID, err := res.userID(link.GuardianID)
+		if err != nil {
+			return err
+		}
+		childID, err := res.userID(link.ChildID)
+		if err != nil {
+			return err
+		}
+		link.GuardianID = guardianID
+		link.ChildID = childID
+		if _, err := res.store.CreateGuardianLink(res.ctx, link); err != nil {
+			return fmt.Errorf("guardian link %d: %w", link.ID, err)
+		}
+		res.report.GuardianLinks++
+	}
+	return nil
+}
+
+// restoreAuditLog 還原稽核記錄，系統操作者維持為 0
+func (res *restorer) restoreAuditLog() error {
+	var entries []models.AuditEntry
+	if err := readJSON(res.archive, auditLogFile, &entries); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		orgID, ok := res.orgs[entry.OrgID]
+		if !ok {
+			return fmt.Errorf("audit entry %d: organization %d missing from backup", entry.ID, entry.OrgID)
+		}
+		targetID, err := res.userID(entry.TargetUserID)
+		if err != nil {
+			return err
+		}
+		if entry.ActorID != 0 {
+			if entry.ActorID, err = res.userID(entry.ActorID); err != nil {
+				return err
+			}
+		}
+		entry.OrgID = orgID
+		entry.TargetUserID = targetID
+		if _, err := res.store.CreateAuditEntry(res.ctx, entry); err != nil {
+			return err
+		}
+		res.report.AuditEntries++
+	}
+	return nil
+}
+
+// userID 返回備份中用戶在目標儲存中的ID
+func (res *restorer) userID(oldID int) (int, error) {
+	id, ok := res.users[oldID]
+	if !ok {
+		return 0, fmt.Errorf("user %d missing from backup", oldID)
+	}
+	return id, nil
+}
+
+// characterID 返回字元在目標儲存中的ID，內建字元維持原ID
+func (res *restorer) characterID(oldID int) int {
+	if id, ok := res.characters[oldID]; ok {
+		return id
+	}
+	return oldID
+}
+
+// characterIDs 轉換字元ID列表
+func (res *restorer) characterIDs(oldIDs []int) []int {
+	ids := make([]int, len(oldIDs))
+	for i, id := range oldIDs {
+		ids[i] = res.characterID(id)
+	}
+	return ids
+}
+
+// readJSON 讀取壓縮檔中的 JSON 檔案
+func readJSON(archive *zip.Reader, name string, value interface{}) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("backup is missing %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
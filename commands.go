@@ -0,0 +1,181 @@
+// backend/commands.go
+package main
+
+import (
+	"backend/accounts"
+	"backend/achievements"
+	"backend/backup"
+	"backend/export"
+	"backend/storage"
+	"backend/storage/memory"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// runCommand 執行命令列子命令
+func runCommand(ctx context.Context, store storage.Storage, name string, args []string) error {
+	switch name {
+	case "export":
+		return runExport(ctx, store, args)
+	case "backup":
+		return runBackup(ctx, store, args)
+	case "restore":
+		return runRestore(ctx, store, args)
+	case "purge-accounts":
+		purged, err := accounts.PurgeDue(ctx, store, time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d accounts\n", purged)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// runExport 將用戶的學習資料匯出為 zip 壓縮檔
+// 用法: backend export -org default -user 1 -out user-1.zip
+func runExport(ctx context.Context, store storage.Storage, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	orgSlug := flags.String("org", "default", "organisation slug")
+	userID := flags.Int("user", 0, "user ID to export")
+	out := flags.String("out", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *userID <= 0 {
+		return fmt.Errorf("export: -user is required")
+	}
+	if err := requirePersistent(store, "export"); err != nil {
+		return err
+	}
+
+	org, err := store.GetOrganizationBySlug(ctx, *orgSlug)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	exporter := export.NewExporter(store, achievements.NewEngine(store, achievements.DefaultRules))
+	return exporter.WriteArchive(ctx, w, org.ID, *userID)
+}
+
+// runBackup 將整個儲存備份為 zip 壓縮檔
+// 用法: backend backup -out backup.zip
+func runBackup(ctx context.Context, store storage.Storage, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	out := flags.String("out", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requirePersistent(store, "backup"); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	manifest, err := backup.Write(ctx, store, w)
+	if err != nil {
+		return err
+	}
+	log.Printf("backup written: %d users, %d stroke records",
+		manifest.Counts["users.json"], manifest.Counts["stroke_records.jsonl"])
+	return nil
+}
+
+// runRestore 將備份檔還原至目前的儲存，完成後寫入快照
+// 用法: backend restore -in backup.zip
+func runRestore(ctx context.Context, store storage.Storage, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	in := flags.String("in", "", "backup file to restore")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return fmt.Errorf("restore: -in is required")
+	}
+	if err := requirePersistent(store, "restore"); err != nil {
+		return err
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	report, err := backup.Restore(ctx, store, file, info.Size())
+	if err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		log.Printf("warning: %s", warning)
+	}
+	fmt.Printf("Restored %d organizations, %d users, %d characters, %d decks, %d classrooms, %d assignments, %d guardian links, %d achievements, %d audit entries, %d stroke records\n",
+		report.Organizations, report.Users, report.Characters, report.Decks, report.Classrooms,
+		report.Assignments, report.GuardianLinks, report.Achievements, report.AuditEntries, report.StrokeRecords)
+
+	if snapshotter, ok := store.(interface{ Snapshot() error }); ok {
+		return snapshotter.Snapshot()
+	}
+	return nil
+}
+
+// requirePersistent 確認命令列工具讀寫的是 DATA_DIR 中的資料，
+// 未設定時儲存只有內建的預設資料，還原的內容也會在程式結束時遺失
+func requirePersistent(store storage.Storage, command string) error {
+	if persistent, ok := store.(interface{ Persistent() bool }); ok && persistent.Persistent() {
+		return nil
+	}
+	return fmt.Errorf("%s: DATA_DIR must be set to the server's data directory", command)
+}
+
+// purgeDeletedAccounts 每隔一段時間清除寬限期已結束的帳號
+func purgeDeletedAccounts(store storage.Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if purged, err := accounts.PurgeDue(context.Background(), store, now); err != nil {
+			log.Printf("account purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("purged %d deleted accounts", purged)
+		}
+	}
+}
+
+// snapshotPeriodically 每隔一段時間將記憶體儲存寫入快照
+func snapshotPeriodically(store *memory.MemoryStorage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := store.Snapshot(); err != nil {
+			log.Printf("snapshot failed: %v", err)
+		}
+	}
+}
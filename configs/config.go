@@ -12,13 +12,18 @@ import (
 
 // Config 應用程式配置
 type Config struct {
-	Port             string
-	JWTSecret        []byte
-	JWTExpiryTime    time.Duration
-	AllowedOrigins   []string
-	AllowedMethods   []string
-	AllowedHeaders   []string
-	AllowCredentials bool
+	Port              string
+	JWTSecret         []byte
+	JWTExpiryTime     time.Duration
+	AllowedOrigins    []string
+	AllowedMethods    []string
+	AllowedHeaders    []string
+	AllowCredentials  bool
+	DataDir           string        // 快照與日誌的存放目錄，空字串表示只保存在記憶體中
+	SnapshotInterval  time.Duration // 寫入快照的間隔
+	CharacterCacheTTL time.Duration // 字元資料的快取時間，0 表示不快取
+	LocalesDir        string        // 訊息目錄的存放目錄，空字串表示使用內建的目錄
+	DefaultLocale     string        // 客戶端未指定或不支援的語系時使用的語系
 }
 
 // LoadConfig 從環境變數載入配置
@@ -30,13 +35,18 @@ func LoadConfig() *Config {
 
 	// 設置默認值
 	config := &Config{
-		Port:             getEnv("PORT", "8080"),
-		JWTSecret:        []byte(getEnv("JWT_SECRET", "your_secure_secret_key")),
-		JWTExpiryTime:    time.Duration(getEnvAsInt("JWT_EXPIRY_HOURS", 24)) * time.Hour,
-		AllowedOrigins:   []string{getEnv("ALLOWED_ORIGINS", "*")},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
-		AllowCredentials: getEnvAsBool("ALLOW_CREDENTIALS", true),
+		Port:              getEnv("PORT", "8080"),
+		JWTSecret:         []byte(getEnv("JWT_SECRET", "your_secure_secret_key")),
+		JWTExpiryTime:     time.Duration(getEnvAsInt("JWT_EXPIRY_HOURS", 24)) * time.Hour,
+		AllowedOrigins:    []string{getEnv("ALLOWED_ORIGINS", "*")},
+		AllowedMethods:    []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:    []string{"Content-Type", "Authorization", "If-None-Match", "X-Request-ID", "Accept-Language"},
+		AllowCredentials:  getEnvAsBool("ALLOW_CREDENTIALS", true),
+		DataDir:           getEnv("DATA_DIR", ""),
+		SnapshotInterval:  time.Duration(getEnvAsInt("SNAPSHOT_INTERVAL_MINUTES", 10)) * time.Minute,
+		CharacterCacheTTL: time.Duration(getEnvAsInt("CHARACTER_CACHE_TTL_SECONDS", 300)) * time.Second,
+		LocalesDir:        getEnv("LOCALES_DIR", ""),
+		DefaultLocale:     getEnv("DEFAULT_LOCALE", "zh-TW"),
 	}
 
 	return config
@@ -0,0 +1,328 @@
+// backend/export/archive.go
+package export
+
+import (
+	"archive/zip"
+	"backend/achievements"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recordPageSize 逐頁讀取筆畫記錄時每頁的筆數
+const recordPageSize = 500
+
+// Profile 匯出檔中的用戶基本資料與學習設定
+type Profile struct {
+	User         models.User          `json:"user"`
+	Organization *models.Organization `json:"organization,omitempty"`
+	DailyGoal    models.DailyGoal     `json:"dailyGoal"`
+	Streak       models.Streak        `json:"streak"`
+	ExportedAt   time.Time            `json:"exportedAt"`
+}
+
+// Exporter 將學習者的完整資料匯出為包含 JSON 與 CSV 檔案的 zip 壓縮檔
+type Exporter struct {
+	store        storage.Storage
+	achievements *achievements.Engine
+}
+
+// NewExporter 創建一個新的匯出器
+func NewExporter(store storage.Storage, engine *achievements.Engine) *Exporter {
+	return &Exporter{
+		store:        store,
+		achievements: engine,
+	}
+}
+
+// WriteArchive 將組織內用戶的資料寫入 zip 壓縮檔，筆畫記錄逐頁讀取一次並同時寫出 JSON 與 CSV
+func (e *Exporter) WriteArchive(ctx context.Context, w io.Writer, orgID, userID int) error {
+	profile, err := e.loadProfile(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	userProgress, err := e.store.GetUserProgress(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	allStatuses, err := e.achievements.Statuses(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	if err := writeJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	progress := sortedProgress(userProgress)
+	if err := writeJSON(archive, "progress.json", progress); err != nil {
+		return err
+	}
+	if err := writeCSV(archive, "progress.csv", progressCSVHeader, progressRows(progress)); err != nil {
+		return err
+	}
+
+	statuses := []models.AchievementStatus{}
+	for _, status := range allStatuses {
+		if status.Unlocked {
+			statuses = append(statuses, status)
+		}
+	}
+	if err := writeJSON(archive, "achievements.json", statuses); err != nil {
+		return err
+	}
+	if err := writeCSV(archive, "achievements.csv", achievementCSVHeader, achievementRows(statuses)); err != nil {
+		return err
+	}
+
+	if err := e.writeStrokeRecords(ctx, archive, orgID, userID); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// loadProfile 載入用戶基本資料、所屬組織、每日目標與連續練習記錄
+func (e *Exporter) loadProfile(ctx context.Context, orgID, userID int) (*Profile, error) {
+	user, err := e.store.GetUserByID(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	org, err := e.store.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	goal, err := e.store.GetDailyGoal(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	streak, err := e.store.GetStreak(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Profile{
+		User:         *user,
+		Organization: org,
+		DailyGoal:    goal,
+		Streak:       streak,
+		ExportedAt:   time.Now(),
+	}, nil
+}
+
+// eachStrokeRecordPage 逐頁讀取用戶的所有筆畫記錄
+func (e *Exporter) eachStrokeRecordPage(ctx context.Context, orgID, userID int, fn func(records []models.StrokeRecord) error) error {
+	query := models.StrokeRecordQuery{UserID: userID, Limit: recordPageSize}
+	for {
+		page, err := e.store.QueryStrokeRecords(ctx, orgID, query)
+		if err != nil {
+			return err
+		}
+		if err := fn(page.Records); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// writeStrokeRecords 只讀取一次筆畫記錄，同時寫出 JSON 陣列與 CSV，確保兩個檔案內容一致
+// zip 同一時間只能寫入一個檔案，因此 CSV 先暫存於臨時檔，JSON 寫完後再複製進壓縮檔
+func (e *Exporter) writeStrokeRecords(ctx context.Context, archive *zip.Writer, orgID, userID int) error {
+	spool, err := os.CreateTemp("", "stroke-records-*.csv")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	file, err := createFile(archive, "stroke_records.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, "["); err != nil {
+		return err
+	}
+
+	// CSV 的路徑以「x y;x y」格式存放於單一欄位
+	writer := csv.NewWriter(spool)
+	if err := writer.Write(strokeRecordCSVHeader); err != nil {
+		return err
+	}
+
+	first := true
+	err = e.eachStrokeRecordPage(ctx, orgID, userID, func(records []models.StrokeRecord) error {
+		for _, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(file, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := file.Write(data); err != nil {
+				return err
+			}
+			if err := writer.Write(strokeRecordRow(record)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(file, "]\n"); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	file, err = createFile(archive, "stroke_records.csv")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, spool)
+	return err
+}
+
+// createFile 在壓縮檔中建立以當前時間標記的檔案
+func createFile(archive *zip.Writer, name string) (io.Writer, error) {
+	return archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+}
+
+// writeJSON 將資料以 JSON 格式寫入壓縮檔
+func writeJSON(archive *zip.Writer, name string, value interface{}) error {
+	file, err := createFile(archive, name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeCSV 將表頭與資料列以 CSV 格式寫入壓縮檔
+func writeCSV(archive *zip.Writer, name string, header []string, rows [][]string) error {
+	file, err := createFile(archive, name)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// CSV 檔案表頭
+var (
+	progressCSVHeader     = []string{"characterId", "attempts", "avgScore", "recentScore", "mastery", "lastStroke", "lastPracticedAt", "masteredAt"}
+	achievementCSVHeader  = []string{"achievementId", "name", "description", "unlockedAt"}
+	strokeRecordCSVHeader = []string{"id", "characterId", "strokeIndex", "score", "clientId", "createdAt", "path"}
+)
+
+// sortedProgress 依字元ID排序用戶進度
+func sortedProgress(progress models.UserProgress) []models.CharacterProgress {
+	result := make([]models.CharacterProgress, 0, len(progress))
+	for _, charProgress := range progress {
+		result = append(result, charProgress)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CharacterID < result[j].CharacterID
+	})
+	return result
+}
+
+// progressRows 將字元進度轉換為 CSV 資料列
+func progressRows(progress []models.CharacterProgress) [][]string {
+	rows := make([][]string, 0, len(progress))
+	for _, p := range progress {
+		masteredAt := ""
+		if p.MasteredAt != nil {
+			masteredAt = formatTime(*p.MasteredAt)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(p.CharacterID),
+			strconv.Itoa(p.Attempts),
+			formatFloat(p.AvgScore),
+			formatFloat(p.RecentScore),
+			formatFloat(p.Mastery),
+			strconv.Itoa(p.LastStroke),
+			formatTime(p.LastPracticedAt),
+			masteredAt,
+		})
+	}
+	return rows
+}
+
+// achievementRows 將已解鎖成就轉換為 CSV 資料列
+func achievementRows(statuses []models.AchievementStatus) [][]string {
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		unlockedAt := ""
+		if status.UnlockedAt != nil {
+			unlockedAt = formatTime(*status.UnlockedAt)
+		}
+		rows = append(rows, []string{status.ID, status.Name, status.Description, unlockedAt})
+	}
+	return rows
+}
+
+// strokeRecordRow 將筆畫記錄轉換為 CSV 資料列
+func strokeRecordRow(record models.StrokeRecord) []string {
+	points := make([]string, len(record.Path))
+	for i, node := range record.Path {
+		points[i] = formatFloat(node.X) + " " + formatFloat(node.Y)
+	}
+	return []string{
+		strconv.Itoa(record.ID),
+		strconv.Itoa(record.CharacterID),
+		strconv.Itoa(record.StrokeIndex),
+		formatFloat(record.Score),
+		record.ClientID,
+		formatTime(record.CreatedAt),
+		strings.Join(points, ";"),
+	}
+}
+
+// formatFloat 以最短表示法格式化數值
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatTime 以 RFC 3339 格式化時間，零值返回空字串
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
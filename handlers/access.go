@@ -0,0 +1,186 @@
+// backend/handlers/access.go
+package handlers
+
+import (
+	"backend/middleware"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"net/http"
+)
+
+// currentUserID 從請求上下文獲取已認證的用戶ID
+func currentUserID(r *http.Request) (int, bool) {
+	value, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		return 0, false
+	}
+
+	// JWT claims 中的數字解析為 float64
+	switch id := value.(type) {
+	case float64:
+		return int(id), true
+	case int:
+		return id, true
+	}
+	return 0, false
+}
+
+// currentRole 從請求上下文獲取已認證用戶的角色
+func currentRole(r *http.Request) string {
+	// 以儲存中的帳號為準，角色變更後不需重新登入即生效
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		return user.Role
+	}
+	role, _ := middleware.GetRoleFromContext(r.Context())
+	return role
+}
+
+// currentOrgID 從請求上下文獲取已認證用戶所屬的組織ID
+func currentOrgID(r *http.Request) int {
+	orgID, ok := middleware.GetOrgIDFromContext(r.Context())
+	if !ok {
+		return models.DefaultOrganizationID
+	}
+	return orgID
+}
+
+// isSelf 檢查已認證用戶是否為目標用戶本人
+func isSelf(r *http.Request, userID int) bool {
+	id, ok := currentUserID(r)
+	return ok && id == userID
+}
+
+// canEditContent 檢查已認證用戶是否可以編輯組織的自訂字元與字卡組：老師或管理員
+func canEditContent(r *http.Request) bool {
+	role := currentRole(r)
+	return role == models.RoleTeacher || role == models.RoleAdmin
+}
+
+// isClassTeacher 檢查已認證用戶是否為班級的老師
+func isClassTeacher(r *http.Request, classroom *models.Classroom) bool {
+	return isSelf(r, classroom.TeacherID)
+}
+
+// canManageClass 檢查已認證用戶是否可以管理班級：班級老師或管理員
+func canManageClass(r *http.Request, classroom *models.Classroom) bool {
+	return isClassTeacher(r, classroom) || currentRole(r) == models.RoleAdmin
+}
+
+// canViewClass 檢查已認證用戶是否可以查看班級：可管理班級者或班級學生
+func canViewClass(store storage.Storage, r *http.Request, classroom *models.Classroom) (bool, error) {
+	if canManageClass(r, classroom) {
+		return true, nil
+	}
+	userID, ok := currentUserID(r)
+	if !ok {
+		return false, nil
+	}
+	return isClassMember(r.Context(), store, classroom, userID)
+}
+
+// authorizeClassView 確認已認證用戶可以查看班級，否則返回 403
+func authorizeClassView(w http.ResponseWriter, r *http.Request, store storage.Storage, classroom *models.Classroom) bool {
+	allowed, err := canViewClass(store, r, classroom)
+	return checkAllowed(w, r, allowed, err)
+}
+
+// isClassMember 檢查用戶是否為班級的學生
+func isClassMember(ctx context.Context, store storage.Storage, classroom *models.Classroom, userID int) (bool, error) {
+	members, err := store.GetClassMembers(ctx, classroom.OrgID, classroom.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if member.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadOrgUser 確認目標用戶屬於已認證用戶的組織，用戶不存在時返回 false 而非錯誤
+func loadOrgUser(store storage.Storage, r *http.Request, userID int) (bool, error) {
+	_, err := store.GetUserByID(r.Context(), currentOrgID(r), userID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// isApprovedGuardian 檢查已認證用戶是否為目標用戶已獲同意的監護人
+func isApprovedGuardian(store storage.Storage, r *http.Request, viewerID, userID int) (bool, error) {
+	links, err := store.GetGuardianLinksByChildID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		return false, err
+	}
+	for _, link := range links {
+		if link.GuardianID == viewerID && link.Status == models.GuardianLinkApproved {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// canViewUser 檢查已認證用戶是否可以查看目標用戶的學習資料：目標用戶必須屬於同一組織，
+// 且已認證用戶為本人、組織管理員、目標用戶所屬班級的老師，或已獲同意的監護人
+func canViewUser(store storage.Storage, r *http.Request, userID int) (bool, error) {
+	if inOrg, err := loadOrgUser(store, r, userID); !inOrg || err != nil {
+		return false, err
+	}
+	if isSelf(r, userID) || currentRole(r) == models.RoleAdmin {
+		return true, nil
+	}
+
+	viewerID, ok := currentUserID(r)
+	if !ok {
+		return false, nil
+	}
+	classrooms, err := store.GetClassroomsByStudentID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		return false, err
+	}
+	for _, classroom := range classrooms {
+		if classroom.TeacherID == viewerID {
+			return true, nil
+		}
+	}
+	return isApprovedGuardian(store, r, viewerID, userID)
+}
+
+// canExportUser 檢查已認證用戶是否可以匯出目標用戶的完整資料：
+// 目標用戶必須屬於同一組織，且已認證用戶為本人、組織管理員或已獲同意的監護人
+func canExportUser(store storage.Storage, r *http.Request, userID int) (bool, error) {
+	if inOrg, err := loadOrgUser(store, r, userID); !inOrg || err != nil {
+		return false, err
+	}
+	if isSelf(r, userID) || currentRole(r) == models.RoleAdmin {
+		return true, nil
+	}
+
+	viewerID, ok := currentUserID(r)
+	if !ok {
+		return false, nil
+	}
+	return isApprovedGuardian(store, r, viewerID, userID)
+}
+
+// authorizeUserRead 確認已認證用戶可以查看目標用戶的資料，否則返回 403
+func authorizeUserRead(w http.ResponseWriter, r *http.Request, store storage.Storage, userID int) bool {
+	allowed, err := canViewUser(store, r, userID)
+	return checkAllowed(w, r, allowed, err)
+}
+
+// checkAllowed 依權限檢查結果回應 403 或儲存錯誤，允許時返回 true
+func checkAllowed(w http.ResponseWriter, r *http.Request, allowed bool, err error) bool {
+	if err != nil {
+		writeStoreError(w, r, err, "Forbidden")
+		return false
+	}
+	if !allowed {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+// backend/handlers/achievement.go
+package handlers
+
+import (
+	"backend/achievements"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// AchievementHandler 處理成就相關的請求
+type AchievementHandler struct {
+	store        storage.Storage
+	achievements *achievements.Engine
+}
+
+// NewAchievementHandler 創建一個新的成就處理器
+func NewAchievementHandler(store storage.Storage, engine *achievements.Engine) *AchievementHandler {
+	return &AchievementHandler{
+		store:        store,
+		achievements: engine,
+	}
+}
+
+// GetUserAchievements 獲取用戶所有成就及解鎖時間
+func (h *AchievementHandler) GetUserAchievements(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	statuses, err := h.achievements.Statuses(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
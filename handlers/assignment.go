@@ -0,0 +1,316 @@
+// backend/handlers/assignment.go
+package handlers
+
+import (
+	"backend/apierror"
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// AssignmentHandler 處理作業相關的請求
+type AssignmentHandler struct {
+	store storage.Storage
+}
+
+// NewAssignmentHandler 創建一個新的作業處理器
+func NewAssignmentHandler(store storage.Storage) *AssignmentHandler {
+	return &AssignmentHandler{
+		store: store,
+	}
+}
+
+// CreateAssignment 老師為班級指派作業
+func (h *AssignmentHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	classroom, ok := loadManagedClassroom(w, r, h.store)
+	if !ok {
+		return
+	}
+
+	assignment, ok := h.decodeAssignment(w, r)
+	if !ok {
+		return
+	}
+	assignment.ClassID = classroom.ID
+
+	created, err := h.store.CreateAssignment(r.Context(), assignment)
+	if err != nil {
+		writeStoreError(w, r, err, "Error creating assignment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetClassAssignments 獲取班級的所有作業，限班級老師與學生
+func (h *AssignmentHandler) GetClassAssignments(w http.ResponseWriter, r *http.Request) {
+	classroom, ok := loadClassroom(w, r, h.store)
+	if !ok {
+		return
+	}
+	if !authorizeClassView(w, r, h.store, classroom) {
+		return
+	}
+
+	assignments, err := h.store.GetAssignmentsByClassID(r.Context(), currentOrgID(r), classroom.ID)
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignments)
+}
+
+// GetAssignment 獲取作業詳情，限班級老師與學生
+func (h *AssignmentHandler) GetAssignment(w http.ResponseWriter, r *http.Request) {
+	assignment, classroom, ok := h.loadAssignment(w, r)
+	if !ok {
+		return
+	}
+	if !authorizeClassView(w, r, h.store, classroom) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// UpdateAssignment 老師更新作業
+func (h *AssignmentHandler) UpdateAssignment(w http.ResponseWriter, r *http.Request) {
+	existing, classroom, ok := h.loadAssignment(w, r)
+	if !ok {
+		return
+	}
+	if !canManageClass(r, classroom) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	assignment, ok := h.decodeAssignment(w, r)
+	if !ok {
+		return
+	}
+	assignment.ID = existing.ID
+
+	updated, err := h.store.UpdateAssignment(r.Context(), assignment)
+	if err != nil {
+		writeStoreError(w, r, err, "Error updating assignment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteAssignment 老師刪除作業
+func (h *AssignmentHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	assignment, classroom, ok := h.loadAssignment(w, r)
+	if !ok {
+		return
+	}
+	if !canManageClass(r, classroom) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	if err := h.store.DeleteAssignment(r.Context(), currentOrgID(r), assignment.ID); err != nil {
+		writeStoreError(w, r, err, "Error deleting assignment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAssignmentStatus 獲取班級每位學生的作業完成狀態，限班級老師
+func (h *AssignmentHandler) GetAssignmentStatus(w http.ResponseWriter, r *http.Request) {
+	assignment, classroom, ok := h.loadAssignment(w, r)
+	if !ok {
+		return
+	}
+	if !canManageClass(r, classroom) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	members, err := h.store.GetClassMembers(r.Context(), currentOrgID(r), classroom.ID)
+	if err != nil {
+		writeStoreError(w, r, err, "Classroom not found")
+		return
+	}
+
+	dueProgress, err := h.store.GetAssignmentDueProgress(r.Context(), currentOrgID(r), assignment.ID)
+	if err != nil {
+		writeStoreError(w, r, err, "Assignment not found")
+		return
+	}
+
+	now := time.Now()
+	statuses := []models.AssignmentStudentStatus{}
+	for _, member := range members {
+		progress, err := h.store.GetUserProgress(r.Context(), currentOrgID(r), member.UserID)
+		if err != nil {
+			writeStoreError(w, r, err, "User not found")
+			return
+		}
+		status := assignment.StatusFor(member.UserID, progress, dueProgress, now)
+		if user, err := h.store.GetUserByID(r.Context(), classroom.OrgID, member.UserID); err == nil {
+			status.Username = user.Username
+		}
+		statuses = append(statuses, status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// GetUserAssignments 獲取學生在所有班級的作業及完成狀態
+// 查詢參數: status（pending 只返回未完成的作業，all 返回全部，預設 pending）
+func (h *AssignmentHandler) GetUserAssignments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	filter := r.URL.Query().Get("status")
+	if filter == "" {
+		filter = "pending"
+	}
+	if filter != "pending" && filter != "all" {
+		writeError(w, r, http.StatusBadRequest, "Invalid status filter")
+		return
+	}
+
+	now := time.Now()
+	progress, err := h.store.GetUserProgress(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	classrooms, err := h.store.GetClassroomsByStudentID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	assignments := []models.StudentAssignment{}
+	for _, classroom := range classrooms {
+		classAssignments, err := h.store.GetAssignmentsByClassID(r.Context(), currentOrgID(r), classroom.ID)
+		if err != nil {
+			writeStoreError(w, r, err, "Classroom not found")
+			return
+		}
+		for _, assignment := range classAssignments {
+			dueProgress, err := h.store.GetAssignmentDueProgress(r.Context(), currentOrgID(r), assignment.ID)
+			if err != nil {
+				writeStoreError(w, r, err, "Assignment not found")
+				return
+			}
+			status := assignment.StatusFor(userID, progress, dueProgress, now)
+			if filter == "pending" && status.Status == models.AssignmentCompleted {
+				continue
+			}
+			assignments = append(assignments, models.StudentAssignment{
+				Assignment: assignment,
+				Progress:   status,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignments)
+}
+
+// loadAssignment 根據路徑參數載入作業及其班級
+func (h *AssignmentHandler) loadAssignment(w http.ResponseWriter, r *http.Request) (*models.Assignment, *models.Classroom, bool) {
+	vars := mux.Vars(r)
+	assignmentID, err := strconv.Atoi(vars["assignmentId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid assignment ID")
+		return nil, nil, false
+	}
+
+	assignment, err := h.store.GetAssignmentByID(r.Context(), currentOrgID(r), assignmentID)
+	if err != nil {
+		writeStoreError(w, r, err, "Assignment not found")
+		return nil, nil, false
+	}
+
+	classroom, err := h.store.GetClassroomByID(r.Context(), currentOrgID(r), assignment.ClassID)
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return nil, nil, false
+	}
+	return assignment, classroom, true
+}
+
+// decodeAssignment 解析並驗證作業請求
+func (h *AssignmentHandler) decodeAssignment(w http.ResponseWriter, r *http.Request) (models.Assignment, bool) {
+	var req models.AssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return models.Assignment{}, false
+	}
+
+	// 驗證請求
+	req.Title = strings.TrimSpace(req.Title)
+	var details []apierror.FieldError
+	if req.Title == "" {
+		details = append(details, invalidField("title", "Title is required"))
+	}
+	if len(req.CharacterIDs) == 0 {
+		details = append(details, invalidField("characterIds", "At least one character is required"))
+	}
+	if req.DueAt.IsZero() {
+		details = append(details, invalidField("dueAt", "Due date is required"))
+	}
+	if req.MasteryThreshold == 0 {
+		req.MasteryThreshold = models.MasteryThreshold
+	}
+	if req.MasteryThreshold < 0 || req.MasteryThreshold > 100 {
+		details = append(details, invalidField("masteryThreshold", "Mastery threshold must be between 0 and 100"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Invalid assignment", details...)
+		return models.Assignment{}, false
+	}
+
+	characters, err := h.store.GetCharacters(r.Context(), currentOrgID(r))
+	if err != nil {
+		writeStoreError(w, r, err, "Invalid request parameters")
+		return models.Assignment{}, false
+	}
+	known := make(map[int]bool)
+	for _, character := range characters {
+		known[character.ID] = true
+	}
+	for _, characterID := range req.CharacterIDs {
+		if !known[characterID] {
+			message := i18n.T(r.Context(), "Unknown character ID %d", characterID)
+			writeValidationError(w, r, message, invalidField("characterIds", message))
+			return models.Assignment{}, false
+		}
+	}
+
+	return models.Assignment{
+		Title:            req.Title,
+		CharacterIDs:     req.CharacterIDs,
+		DueAt:            req.DueAt,
+		MasteryThreshold: req.MasteryThreshold,
+	}, true
+}
@@ -2,10 +2,13 @@
 package handlers
 
 import (
+	"backend/apierror"
 	"backend/configs"
 	"backend/models"
 	"backend/storage"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -30,29 +33,31 @@ func NewAuthHandler(store storage.Storage, config *configs.Config) *AuthHandler
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
 		return
 	}
 
-	// 驗證用戶名和密碼
-	user, err := h.store.GetUserByUsername(req.Username)
-	if err != nil || user.Password != req.Password {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+	org, err := h.loadOrganization(r.Context(), req.Organization)
+	if err != nil {
+		writeStoreError(w, r, err, "Invalid credentials")
 		return
 	}
 
-	// 創建 JWT Token
-	expirationTime := time.Now().Add(h.config.JWTExpiryTime)
-	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"exp":      expirationTime.Unix(),
+	// 驗證用戶名和密碼
+	user, err := h.store.GetUserByUsername(r.Context(), org.ID, req.Username)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		writeStoreError(w, r, err, "Invalid credentials")
+		return
+	}
+	if err != nil || user.DeletedAt != nil || user.Password != req.Password {
+		writeError(w, r, http.StatusUnauthorized, "Invalid credentials")
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(h.config.JWTSecret)
+	// 創建 JWT Token
+	tokenString, err := h.generateToken(user)
 	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, "Error generating token")
 		return
 	}
 
@@ -69,15 +74,42 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Register 處理註冊請求
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var req models.LoginRequest
+	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
 		return
 	}
 
 	// 驗證用戶名和密碼
-	if req.Username == "" || req.Password == "" {
-		http.Error(w, "Username and password are required", http.StatusBadRequest)
+	var details []apierror.FieldError
+	if req.Username == "" {
+		details = append(details, invalidField("username", "Username is required"))
+	}
+	if req.Password == "" {
+		details = append(details, invalidField("password", "Password is required"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Username and password are required", details...)
+		return
+	}
+	if models.IsReservedUsername(req.Username) {
+		writeValidationError(w, r, "Invalid username", invalidField("username", "Username is reserved"))
+		return
+	}
+
+	org, err := h.loadOrganization(r.Context(), req.Organization)
+	if errors.Is(err, storage.ErrNotFound) {
+		writeValidationError(w, r, "Organization not found", invalidField("organization", "Organization not found"))
+		return
+	}
+	if err != nil {
+		writeStoreError(w, r, err, "Organization not found")
+		return
+	}
+
+	// 只允許自行註冊為學生，老師由組織管理員指派
+	if req.Role != "" && req.Role != models.RoleStudent {
+		writeValidationError(w, r, "Invalid role", invalidField("role", "Teachers are assigned by an organization admin"))
 		return
 	}
 
@@ -85,27 +117,21 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	newUser := models.User{
 		Username: req.Username,
 		Password: req.Password,
-		Email:    "", // 可以從請求中獲取或留空
+		Email:    req.Email,
+		Role:     models.RoleStudent,
+		OrgID:    org.ID,
 	}
 
-	user, err := h.store.CreateUser(newUser)
+	user, err := h.store.CreateUser(r.Context(), newUser)
 	if err != nil {
-		http.Error(w, "Error creating user: "+err.Error(), http.StatusBadRequest)
+		writeStoreError(w, r, err, "Username already exists")
 		return
 	}
 
 	// 創建 JWT Token
-	expirationTime := time.Now().Add(h.config.JWTExpiryTime)
-	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"exp":      expirationTime.Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(h.config.JWTSecret)
+	tokenString, err := h.generateToken(user)
 	if err != nil {
-		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, "Error generating token")
 		return
 	}
 
@@ -119,3 +145,26 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Token: tokenString,
 	})
 }
+
+// generateToken 為用戶簽發 JWT Token
+func (h *AuthHandler) generateToken(user *models.User) (string, error) {
+	expirationTime := time.Now().Add(h.config.JWTExpiryTime)
+	claims := jwt.MapClaims{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"role":     user.Role,
+		"org_id":   user.OrgID,
+		"exp":      expirationTime.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(h.config.JWTSecret)
+}
+
+// loadOrganization 根據代稱載入組織，未指定時使用預設組織
+func (h *AuthHandler) loadOrganization(ctx context.Context, slug string) (*models.Organization, error) {
+	if slug == "" {
+		return h.store.GetOrganizationByID(ctx, models.DefaultOrganizationID)
+	}
+	return h.store.GetOrganizationBySlug(ctx, slug)
+}
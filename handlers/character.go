@@ -2,10 +2,17 @@
 package handlers
 
 import (
+	"backend/apierror"
+	"backend/i18n"
+	"backend/models"
 	"backend/storage"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -24,10 +31,13 @@ func NewCharacterHandler(store storage.Storage) *CharacterHandler {
 
 // GetCharacters 獲取所有字元
 func (h *CharacterHandler) GetCharacters(w http.ResponseWriter, r *http.Request) {
-	characters := h.store.GetCharacters()
+	characters, err := h.store.GetCharacters(r.Context(), currentOrgID(r))
+	if err != nil {
+		writeStoreError(w, r, err, "Organization not found")
+		return
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(characters)
+	writeJSONWithETag(w, r, characters)
 }
 
 // GetCharacterByID 根據ID獲取字元詳情
@@ -35,16 +45,219 @@ func (h *CharacterHandler) GetCharacterByID(w http.ResponseWriter, r *http.Reque
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid character ID", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid character ID")
+		return
+	}
+
+	character, err := h.store.GetCharacterByID(r.Context(), currentOrgID(r), id)
+	if err != nil {
+		writeStoreError(w, r, err, "Character not found")
+		return
+	}
+
+	writeJSONWithETag(w, r, character)
+}
+
+// CreateCharacter 創建組織的自訂字元，限老師與管理員
+func (h *CharacterHandler) CreateCharacter(w http.ResponseWriter, r *http.Request) {
+	if !canEditContent(r) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	character, ok := decodeCharacter(w, r)
+	if !ok {
+		return
+	}
+	character.OrgID = currentOrgID(r)
+
+	created, err := h.store.CreateCharacter(r.Context(), character)
+	if err != nil {
+		writeStoreError(w, r, err, "Error creating character")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateCharacter 更新組織的自訂字元，限老師與管理員，內建字元不可修改
+func (h *CharacterHandler) UpdateCharacter(w http.ResponseWriter, r *http.Request) {
+	if !canEditContent(r) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid character ID")
+		return
+	}
+
+	existing, err := h.store.GetCharacterByID(r.Context(), currentOrgID(r), id)
+	if err != nil {
+		writeStoreError(w, r, err, "Character not found")
+		return
+	}
+	if existing.OrgID != currentOrgID(r) {
+		writeError(w, r, http.StatusForbidden, "Built-in characters cannot be modified")
+		return
+	}
+
+	character, ok := decodeCharacter(w, r)
+	if !ok {
+		return
+	}
+	character.ID = existing.ID
+	character.OrgID = existing.OrgID
+
+	updated, err := h.store.UpdateCharacter(r.Context(), character)
+	if err != nil {
+		writeStoreError(w, r, err, "Error updating character")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// GetDecks 獲取所有字卡組
+func (h *CharacterHandler) GetDecks(w http.ResponseWriter, r *http.Request) {
+	decks, err := h.store.GetDecks(r.Context(), currentOrgID(r))
+	if err != nil {
+		writeStoreError(w, r, err, "Organization not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decks)
+}
+
+// CreateDeck 創建組織的自訂字卡組，限老師與管理員
+func (h *CharacterHandler) CreateDeck(w http.ResponseWriter, r *http.Request) {
+	if !canEditContent(r) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	var req models.DeckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	// 驗證請求
+	req.Name = strings.TrimSpace(req.Name)
+	var details []apierror.FieldError
+	if req.Name == "" {
+		details = append(details, invalidField("name", "Name is required"))
+	}
+	if len(req.CharacterIDs) == 0 {
+		details = append(details, invalidField("characterIds", "At least one character is required"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Name and characters are required", details...)
 		return
 	}
 
-	character, err := h.store.GetCharacterByID(id)
+	orgID := currentOrgID(r)
+	characters, err := h.store.GetCharacters(r.Context(), orgID)
 	if err != nil {
-		http.Error(w, "Character not found", http.StatusNotFound)
+		writeStoreError(w, r, err, "Invalid request parameters")
+		return
+	}
+	known := make(map[int]bool)
+	for _, character := range characters {
+		known[character.ID] = true
+	}
+	for _, characterID := range req.CharacterIDs {
+		if !known[characterID] {
+			message := i18n.T(r.Context(), "Unknown character ID %d", characterID)
+			writeValidationError(w, r, message, invalidField("characterIds", message))
+			return
+		}
+	}
+
+	deck, err := h.store.CreateDeck(r.Context(), models.Deck{
+		Name:         req.Name,
+		CharacterIDs: req.CharacterIDs,
+		OrgID:        orgID,
+	})
+	if err != nil {
+		writeStoreError(w, r, err, "Error creating deck")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(deck)
+}
+
+// writeJSONWithETag 以回應內容的雜湊作為 ETag 輸出 JSON，
+// 與 If-None-Match 相符時返回 304，讓客戶端沿用已快取的字元資料
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "Error encoding response")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(character)
+	w.Write(append(body, '\n'))
+}
+
+// etagMatches 判斷 If-None-Match 標頭是否包含指定的 ETag，比較時忽略弱驗證前綴
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCharacter 解析並驗證字元請求
+func decodeCharacter(w http.ResponseWriter, r *http.Request) (models.Character, bool) {
+	var req models.CharacterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return models.Character{}, false
+	}
+
+	// 驗證請求
+	req.Name = strings.TrimSpace(req.Name)
+	var details []apierror.FieldError
+	if req.Name == "" {
+		details = append(details, invalidField("name", "Name is required"))
+	}
+	if len(req.StrokeData) == 0 {
+		details = append(details, invalidField("strokeData", "Stroke data is required"))
+	}
+	for i, stroke := range req.StrokeData {
+		if len(stroke.Nodes) < 2 {
+			details = append(details, invalidField(fmt.Sprintf("strokeData[%d].nodes", i), "Each stroke needs at least two nodes"))
+		}
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Invalid character", details...)
+		return models.Character{}, false
+	}
+
+	return models.Character{
+		Name:       req.Name,
+		SVGUrl:     req.SVGUrl,
+		StrokeData: req.StrokeData,
+	}, true
 }
@@ -0,0 +1,338 @@
+// backend/handlers/classroom.go
+package handlers
+
+import (
+	"backend/analysis"
+	"backend/models"
+	"backend/storage"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// enrolmentCodeLength 班級加入代碼長度
+	enrolmentCodeLength = 6
+	// enrolmentCodeAlphabet 加入代碼使用的字元，排除容易混淆的 0、O、1、I
+	enrolmentCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// enrolmentCodeAttempts 產生不重複代碼的最大嘗試次數
+	enrolmentCodeAttempts = 5
+	// defaultInactiveDays 超過此天數未練習的學生列為未活躍
+	defaultInactiveDays = 7
+)
+
+// ClassroomHandler 處理班級相關的請求
+type ClassroomHandler struct {
+	store storage.Storage
+}
+
+// NewClassroomHandler 創建一個新的班級處理器
+func NewClassroomHandler(store storage.Storage) *ClassroomHandler {
+	return &ClassroomHandler{
+		store: store,
+	}
+}
+
+// CreateClassroom 老師創建班級
+func (h *ClassroomHandler) CreateClassroom(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := currentUserID(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if role := currentRole(r); role != models.RoleTeacher && role != models.RoleAdmin {
+		writeError(w, r, http.StatusForbidden, "Only teachers can create classes")
+		return
+	}
+
+	var req models.CreateClassroomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeValidationError(w, r, "Class name is required", invalidField("name", "Name is required"))
+		return
+	}
+
+	// 產生加入代碼，代碼重複時重試
+	var classroom *models.Classroom
+	var err error
+	for attempt := 0; attempt < enrolmentCodeAttempts; attempt++ {
+		var code string
+		code, err = generateEnrolmentCode()
+		if err != nil {
+			break
+		}
+		classroom, err = h.store.CreateClassroom(r.Context(), models.Classroom{
+			OrgID:         currentOrgID(r),
+			Name:          req.Name,
+			TeacherID:     teacherID,
+			EnrolmentCode: code,
+		})
+		if !errors.Is(err, storage.ErrConflict) {
+			break
+		}
+	}
+	if err != nil {
+		writeStoreError(w, r, err, "Error creating class")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(classroom)
+}
+
+// GetMyClassrooms 獲取已認證用戶任教或加入的班級
+func (h *ClassroomHandler) GetMyClassrooms(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	classrooms, err := h.store.GetClassroomsByTeacherID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	joined, err := h.store.GetClassroomsByStudentID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	for _, classroom := range joined {
+		// 學生不需要看到加入代碼
+		classroom.EnrolmentCode = ""
+		classrooms = append(classrooms, classroom)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(classrooms)
+}
+
+// JoinClassroom 學生以加入代碼加入班級
+func (h *ClassroomHandler) JoinClassroom(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.JoinClassroomRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	classroom, err := h.store.GetClassroomByCode(r.Context(), currentOrgID(r), strings.ToUpper(strings.TrimSpace(req.EnrolmentCode)))
+	if err != nil {
+		writeStoreError(w, r, err, "Invalid enrolment code")
+		return
+	}
+	if classroom.TeacherID == userID {
+		writeError(w, r, http.StatusBadRequest, "Teachers cannot join their own class")
+		return
+	}
+
+	membership, err := h.store.AddClassMember(r.Context(), classroom.ID, userID)
+	if err != nil {
+		writeStoreError(w, r, err, "Already a member of this class")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(membership)
+}
+
+// GetClassroom 獲取班級資料，限班級老師與學生
+func (h *ClassroomHandler) GetClassroom(w http.ResponseWriter, r *http.Request) {
+	classroom, ok := loadClassroom(w, r, h.store)
+	if !ok {
+		return
+	}
+
+	if !authorizeClassView(w, r, h.store, classroom) {
+		return
+	}
+	if !canManageClass(r, classroom) {
+		classroom.EnrolmentCode = ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(classroom)
+}
+
+// GetRoster 獲取班級學生名單，限班級老師
+func (h *ClassroomHandler) GetRoster(w http.ResponseWriter, r *http.Request) {
+	classroom, ok := loadManagedClassroom(w, r, h.store)
+	if !ok {
+		return
+	}
+
+	members, err := h.store.GetClassMembers(r.Context(), currentOrgID(r), classroom.ID)
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return
+	}
+
+	roster := []models.RosterEntry{}
+	for _, member := range members {
+		entry := models.RosterEntry{UserID: member.UserID, JoinedAt: member.JoinedAt}
+		if user, err := h.store.GetUserByID(r.Context(), classroom.OrgID, member.UserID); err == nil {
+			entry.Username = user.Username
+		}
+		roster = append(roster, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roster)
+}
+
+// GetStudentProgress 獲取班級中某位學生的學習進度，限班級老師
+func (h *ClassroomHandler) GetStudentProgress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.loadStudent(w, r)
+	if !ok {
+		return
+	}
+
+	progress, err := h.store.GetUserProgress(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetStudentStrokeRecords 獲取班級中某位學生的筆畫記錄，限班級老師
+// 查詢參數與回應格式與 GET /users/{userId}/stroke-records 相同
+func (h *ClassroomHandler) GetStudentStrokeRecords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.loadStudent(w, r)
+	if !ok {
+		return
+	}
+
+	query, ok := parseStrokeRecordQuery(w, r, userID)
+	if !ok {
+		return
+	}
+
+	page, err := h.store.QueryStrokeRecords(r.Context(), currentOrgID(r), query)
+	if err != nil {
+		writeStoreError(w, r, err, "Error fetching stroke records")
+		return
+	}
+
+	writeStrokeRecords(w, query, page)
+}
+
+// GetDashboard 獲取班級學習分析，限班級老師
+// 查詢參數: inactiveDays（超過幾天未練習視為未活躍，預設 7）
+func (h *ClassroomHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	classroom, ok := loadManagedClassroom(w, r, h.store)
+	if !ok {
+		return
+	}
+
+	inactiveDays := defaultInactiveDays
+	if value := r.URL.Query().Get("inactiveDays"); value != "" {
+		var err error
+		inactiveDays, err = strconv.Atoi(value)
+		if err != nil || inactiveDays <= 0 {
+			writeValidationError(w, r, "Invalid inactiveDays", invalidField("inactiveDays", "Must be a positive number of days"))
+			return
+		}
+	}
+
+	now := time.Now()
+	dashboard, err := analysis.ClassDashboard(r.Context(), h.store, classroom, now, now.AddDate(0, 0, -inactiveDays))
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dashboard)
+}
+
+// loadClassroom 根據路徑參數載入班級
+func loadClassroom(w http.ResponseWriter, r *http.Request, store storage.Storage) (*models.Classroom, bool) {
+	vars := mux.Vars(r)
+	classID, err := strconv.Atoi(vars["classId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid class ID")
+		return nil, false
+	}
+
+	classroom, err := store.GetClassroomByID(r.Context(), currentOrgID(r), classID)
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return nil, false
+	}
+	return classroom, true
+}
+
+// loadManagedClassroom 載入班級並確認已認證用戶是班級老師或管理員
+func loadManagedClassroom(w http.ResponseWriter, r *http.Request, store storage.Storage) (*models.Classroom, bool) {
+	classroom, ok := loadClassroom(w, r, store)
+	if !ok {
+		return nil, false
+	}
+	if !canManageClass(r, classroom) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return nil, false
+	}
+	return classroom, true
+}
+
+// loadStudent 確認已認證用戶是班級老師且路徑中的用戶是班級學生
+func (h *ClassroomHandler) loadStudent(w http.ResponseWriter, r *http.Request) (int, bool) {
+	classroom, ok := loadManagedClassroom(w, r, h.store)
+	if !ok {
+		return 0, false
+	}
+
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return 0, false
+	}
+	member, err := isClassMember(r.Context(), h.store, classroom, userID)
+	if err != nil {
+		writeStoreError(w, r, err, "Class not found")
+		return 0, false
+	}
+	if !member {
+		writeError(w, r, http.StatusNotFound, "Student not found in class")
+		return 0, false
+	}
+	return userID, true
+}
+
+// generateEnrolmentCode 產生隨機的班級加入代碼
+func generateEnrolmentCode() (string, error) {
+	code := make([]byte, enrolmentCodeLength)
+	max := big.NewInt(int64(len(enrolmentCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = enrolmentCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
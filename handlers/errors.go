@@ -0,0 +1,54 @@
+// backend/handlers/errors.go
+package handlers
+
+import (
+	"backend/apierror"
+	"backend/i18n"
+	"backend/storage"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// storeErrorStatus 將儲存錯誤對應到 HTTP 狀態碼
+func storeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError 以統一的錯誤格式回應，錯誤代碼由狀態碼決定，訊息依請求的語系翻譯
+// 含參數的訊息由呼叫者先以 i18n.T 翻譯後傳入
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	apierror.Write(w, r, status, apierror.CodeForStatus(status), i18n.T(r.Context(), message))
+}
+
+// writeValidationError 回應 400 並列出驗證失敗的欄位，訊息與欄位說明依請求的語系翻譯
+func writeValidationError(w http.ResponseWriter, r *http.Request, message string, details ...apierror.FieldError) {
+	for i := range details {
+		details[i].Message = i18n.T(r.Context(), details[i].Message)
+	}
+	apierror.Write(w, r, http.StatusBadRequest, apierror.CodeValidationFailed, i18n.T(r.Context(), message), details...)
+}
+
+// invalidField 建立單一欄位的驗證錯誤
+func invalidField(field, message string) apierror.FieldError {
+	return apierror.FieldError{Field: field, Message: message}
+}
+
+// writeStoreError 依儲存錯誤類型回應；message 用於查無資料、衝突與無效參數等客戶端錯誤，
+// 其他錯誤連同追蹤ID記錄到日誌並回應 500
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	status := storeErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Printf("request %s: storage error: %v", apierror.RequestIDFromContext(r.Context()), err)
+		message = "Internal server error"
+	}
+	writeError(w, r, status, message)
+}
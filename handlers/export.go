@@ -0,0 +1,69 @@
+// backend/handlers/export.go
+package handlers
+
+import (
+	"backend/export"
+	"backend/storage"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ExportHandler 處理學習資料匯出的請求
+type ExportHandler struct {
+	store    storage.Storage
+	exporter *export.Exporter
+}
+
+// NewExportHandler 創建一個新的匯出處理器
+func NewExportHandler(store storage.Storage, exporter *export.Exporter) *ExportHandler {
+	return &ExportHandler{
+		store:    store,
+		exporter: exporter,
+	}
+}
+
+// ExportUserData 以 zip 壓縮檔匯出用戶的基本資料、進度、成就與所有筆畫記錄
+func (h *ExportHandler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 僅本人、管理員或已獲同意的監護人可以匯出
+	allowed, err := canExportUser(h.store, r, userID)
+	if !checkAllowed(w, r, allowed, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%d-export.zip\"", userID))
+
+	// 壓縮檔直接寫入響應；尚未寫出時仍可回應錯誤，開始寫出後只能中斷連線，讓客戶端知道檔案不完整
+	out := &startedWriter{ResponseWriter: w}
+	if err := h.exporter.WriteArchive(r.Context(), out, currentOrgID(r), userID); err != nil {
+		log.Printf("export of user %d failed: %v", userID, err)
+		if !out.started {
+			w.Header().Del("Content-Disposition")
+			writeError(w, r, http.StatusInternalServerError, "Error exporting user data")
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// startedWriter 記錄響應是否已開始寫出
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
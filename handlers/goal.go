@@ -0,0 +1,139 @@
+// backend/handlers/goal.go
+package handlers
+
+import (
+	"backend/apierror"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// GoalHandler 處理每日目標與連續練習相關的請求
+type GoalHandler struct {
+	store storage.Storage
+}
+
+// NewGoalHandler 創建一個新的目標處理器
+func NewGoalHandler(store storage.Storage) *GoalHandler {
+	return &GoalHandler{
+		store: store,
+	}
+}
+
+// GetDailyGoalStatus 獲取用戶今日的目標完成狀態
+func (h *GoalHandler) GetDailyGoalStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	goal, err := h.store.GetDailyGoal(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	activity, err := h.store.GetDailyActivity(r.Context(), currentOrgID(r), userID, goal.LocalDate(time.Now()))
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal.Status(activity))
+}
+
+// UpdateDailyGoal 更新用戶的每日目標
+func (h *GoalHandler) UpdateDailyGoal(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 只能修改自己的目標
+	if !isSelf(r, userID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	var goal models.DailyGoal
+	if err := json.NewDecoder(r.Body).Decode(&goal); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	// 驗證請求
+	var details []apierror.FieldError
+	if goal.TargetCharacters < 0 {
+		details = append(details, invalidField("targetCharacters", "Must not be negative"))
+	}
+	if goal.TargetMinutes < 0 {
+		details = append(details, invalidField("targetMinutes", "Must not be negative"))
+	}
+	if goal.TargetCharacters == 0 && goal.TargetMinutes == 0 {
+		details = append(details, invalidField("targetCharacters", "Set a character or minute target"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Invalid goal targets", details...)
+		return
+	}
+	if goal.Timezone == "" {
+		goal.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(goal.Timezone); err != nil {
+		writeValidationError(w, r, "Invalid time zone", invalidField("timezone", "Must be an IANA time zone name"))
+		return
+	}
+
+	goal.UserID = userID
+	if err := h.store.SetDailyGoal(r.Context(), goal); err != nil {
+		writeStoreError(w, r, err, "Error saving daily goal")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(goal)
+}
+
+// GetStreak 獲取用戶目前與最長的連續練習天數
+func (h *GoalHandler) GetStreak(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	goal, err := h.store.GetDailyGoal(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	streak, err := h.store.GetStreak(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	streak.Current = streak.CurrentOn(goal.LocalDate(time.Now()))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(streak)
+}
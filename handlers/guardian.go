@@ -0,0 +1,163 @@
+// backend/handlers/guardian.go
+package handlers
+
+import (
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// GuardianHandler 處理監護人連結相關的請求
+type GuardianHandler struct {
+	store storage.Storage
+}
+
+// NewGuardianHandler 創建一個新的監護人處理器
+func NewGuardianHandler(store storage.Storage) *GuardianHandler {
+	return &GuardianHandler{
+		store: store,
+	}
+}
+
+// InviteChild 監護人邀請孩子帳號建立連結，需孩子帳號同意後生效
+func (h *GuardianHandler) InviteChild(w http.ResponseWriter, r *http.Request) {
+	guardianID, ok := currentUserID(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req models.GuardianInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	child, err := h.store.GetUserByUsername(r.Context(), currentOrgID(r), req.ChildUsername)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	if child.ID == guardianID {
+		writeError(w, r, http.StatusBadRequest, "Cannot invite yourself")
+		return
+	}
+
+	link, err := h.store.CreateGuardianLink(r.Context(), models.GuardianLink{
+		GuardianID: guardianID,
+		ChildID:    child.ID,
+		Status:     models.GuardianLinkPending,
+	})
+	if err != nil {
+		writeStoreError(w, r, err, "Guardian link already exists")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+// GetMyGuardianLinks 獲取已認證用戶作為監護人或孩子的所有連結
+func (h *GuardianHandler) GetMyGuardianLinks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(r)
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	links, err := h.store.GetGuardianLinksByGuardianID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	childLinks, err := h.store.GetGuardianLinksByChildID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+	links = append(links, childLinks...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// ApproveGuardianLink 孩子帳號同意監護人邀請
+func (h *GuardianHandler) ApproveGuardianLink(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, models.GuardianLinkApproved)
+}
+
+// RejectGuardianLink 孩子帳號拒絕監護人邀請
+func (h *GuardianHandler) RejectGuardianLink(w http.ResponseWriter, r *http.Request) {
+	h.respond(w, r, models.GuardianLinkRejected)
+}
+
+// DeleteGuardianLink 監護人或孩子帳號解除連結
+func (h *GuardianHandler) DeleteGuardianLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := h.loadLink(w, r)
+	if !ok {
+		return
+	}
+	if !isSelf(r, link.GuardianID) && !isSelf(r, link.ChildID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	if err := h.store.DeleteGuardianLink(r.Context(), currentOrgID(r), link.ID); err != nil {
+		writeStoreError(w, r, err, "Error deleting guardian link")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respond 以孩子帳號身份回覆待處理的邀請
+func (h *GuardianHandler) respond(w http.ResponseWriter, r *http.Request, status string) {
+	link, ok := h.loadLink(w, r)
+	if !ok {
+		return
+	}
+	if !isSelf(r, link.ChildID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+	if link.Status != models.GuardianLinkPending {
+		writeError(w, r, http.StatusConflict, "Invitation already answered")
+		return
+	}
+
+	now := time.Now()
+	link.Status = status
+	link.RespondedAt = &now
+
+	updated, err := h.store.UpdateGuardianLink(r.Context(), *link)
+	if err != nil {
+		writeStoreError(w, r, err, "Error updating guardian link")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// loadLink 根據路徑參數載入監護人連結
+func (h *GuardianHandler) loadLink(w http.ResponseWriter, r *http.Request) (*models.GuardianLink, bool) {
+	vars := mux.Vars(r)
+	linkID, err := strconv.Atoi(vars["linkId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid guardian link ID")
+		return nil, false
+	}
+
+	link, err := h.store.GetGuardianLinkByID(r.Context(), currentOrgID(r), linkID)
+	if err != nil {
+		writeStoreError(w, r, err, "Guardian link not found")
+		return nil, false
+	}
+	return link, true
+}
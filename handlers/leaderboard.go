@@ -0,0 +1,111 @@
+// backend/handlers/leaderboard.go
+package handlers
+
+import (
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 排行榜筆數的預設與上限
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// LeaderboardHandler 處理排行榜相關的請求
+type LeaderboardHandler struct {
+	store storage.Storage
+}
+
+// NewLeaderboardHandler 創建一個新的排行榜處理器
+func NewLeaderboardHandler(store storage.Storage) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		store: store,
+	}
+}
+
+// GetLeaderboard 獲取排行榜
+// 查詢參數: period（week、month、all）、metric（mastered、accuracy、volume）、limit、classId（限定班級）
+func (h *LeaderboardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	period := models.LeaderboardPeriod(params.Get("period"))
+	switch period {
+	case "":
+		period = models.LeaderboardWeekly
+	case models.LeaderboardWeekly, models.LeaderboardMonthly, models.LeaderboardAllTime:
+	default:
+		writeValidationError(w, r, "Invalid period", invalidField("period", "Must be week, month or all"))
+		return
+	}
+
+	metric := models.LeaderboardMetric(params.Get("metric"))
+	switch metric {
+	case "":
+		metric = models.MetricMastered
+	case models.MetricMastered, models.MetricAccuracy, models.MetricVolume:
+	default:
+		writeValidationError(w, r, "Invalid metric", invalidField("metric", "Must be mastered, accuracy or volume"))
+		return
+	}
+
+	limit := defaultLeaderboardLimit
+	if value := params.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+			writeValidationError(w, r, "Invalid limit", invalidField("limit", i18n.T(r.Context(), "Must be between 1 and %d", maxLeaderboardLimit)))
+			return
+		}
+	}
+
+	// 班級排行榜只限班級老師與學生查看
+	var userIDs []int
+	if value := params.Get("classId"); value != "" {
+		classID, err := strconv.Atoi(value)
+		if err != nil {
+			writeValidationError(w, r, "Invalid class ID", invalidField("classId", "Must be a class ID"))
+			return
+		}
+		classroom, err := h.store.GetClassroomByID(r.Context(), currentOrgID(r), classID)
+		if err != nil {
+			writeStoreError(w, r, err, "Class not found")
+			return
+		}
+
+		if !authorizeClassView(w, r, h.store, classroom) {
+			return
+		}
+
+		members, err := h.store.GetClassMembers(r.Context(), currentOrgID(r), classID)
+		if err != nil {
+			writeStoreError(w, r, err, "Class not found")
+			return
+		}
+		userIDs = []int{}
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	entries, err := h.store.GetLeaderboard(r.Context(), models.LeaderboardQuery{
+		OrgID:   currentOrgID(r),
+		Period:  period,
+		Metric:  metric,
+		UserIDs: userIDs,
+		Limit:   limit,
+		At:      time.Now(),
+	})
+	if err != nil {
+		writeStoreError(w, r, err, "Invalid request parameters")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
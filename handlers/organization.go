@@ -0,0 +1,106 @@
+// backend/handlers/organization.go
+package handlers
+
+import (
+	"backend/apierror"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// slugPattern 組織代稱只允許小寫英數字與連字號
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// OrganizationHandler 處理組織相關的請求
+type OrganizationHandler struct {
+	store storage.Storage
+}
+
+// NewOrganizationHandler 創建一個新的組織處理器
+func NewOrganizationHandler(store storage.Storage) *OrganizationHandler {
+	return &OrganizationHandler{
+		store: store,
+	}
+}
+
+// GetCurrentOrganization 獲取已認證用戶所屬的組織
+func (h *OrganizationHandler) GetCurrentOrganization(w http.ResponseWriter, r *http.Request) {
+	org, err := h.store.GetOrganizationByID(r.Context(), currentOrgID(r))
+	if err != nil {
+		writeStoreError(w, r, err, "Organization not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(org)
+}
+
+// CreateOrganization 創建新的組織及其管理員帳號，限預設組織的管理員
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	if currentOrgID(r) != models.DefaultOrganizationID || currentRole(r) != models.RoleAdmin {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	var req models.CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	// 驗證請求
+	req.Name = strings.TrimSpace(req.Name)
+	var details []apierror.FieldError
+	if req.Name == "" {
+		details = append(details, invalidField("name", "Name is required"))
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		details = append(details, invalidField("slug", "Must contain only lowercase letters, digits and hyphens"))
+	}
+	if req.AdminUsername == "" {
+		details = append(details, invalidField("adminUsername", "Admin username is required"))
+	} else if models.IsReservedUsername(req.AdminUsername) {
+		details = append(details, invalidField("adminUsername", "Username is reserved"))
+	}
+	if req.AdminPassword == "" {
+		details = append(details, invalidField("adminPassword", "Admin password is required"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Invalid organization", details...)
+		return
+	}
+
+	// 組織與管理員帳號在同一交易中建立，管理員建立失敗時不留下沒有管理員的組織
+	var org *models.Organization
+	message := "Organization slug already exists"
+	err := h.store.RunInTx(r.Context(), func(tx storage.Storage) error {
+		var err error
+		org, err = tx.CreateOrganization(r.Context(), models.Organization{
+			Name: req.Name,
+			Slug: req.Slug,
+		})
+		if err != nil {
+			return err
+		}
+
+		message = "Error creating organization admin"
+		_, err = tx.CreateUser(r.Context(), models.User{
+			Username: req.AdminUsername,
+			Password: req.AdminPassword,
+			Role:     models.RoleAdmin,
+			OrgID:    org.ID,
+		})
+		return err
+	})
+	if err != nil {
+		writeStoreError(w, r, err, message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(org)
+}
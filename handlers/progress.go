@@ -2,10 +2,13 @@
 package handlers
 
 import (
+	"backend/analysis"
+	"backend/models"
 	"backend/storage"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -27,13 +30,134 @@ func (h *ProgressHandler) GetUserProgress(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["userId"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
 		return
 	}
 
 	// 獲取用戶進度
-	progress := h.store.GetUserProgress(userID)
+	progress, err := h.store.GetUserProgress(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(progress)
 }
+
+// 進度歷史查詢的預設與上限
+const (
+	defaultHistoryDays = 30
+	maxHistoryDays     = 366
+	maxHistoryWeeks    = 156
+)
+
+// GetProgressHistory 獲取用戶按日或按週彙總的進度歷史
+// 查詢參數: from、to（YYYY-MM-DD，包含當日）、interval（day 或 week）、tz（IANA 時區）
+func (h *ProgressHandler) GetProgressHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	params := r.URL.Query()
+
+	loc := time.UTC
+	if tz := params.Get("tz"); tz != "" {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			writeValidationError(w, r, "Invalid time zone", invalidField("tz", "Must be an IANA time zone name"))
+			return
+		}
+	}
+
+	interval := models.HistoryInterval(params.Get("interval"))
+	if interval == "" {
+		interval = models.HistoryIntervalDay
+	}
+	if interval != models.HistoryIntervalDay && interval != models.HistoryIntervalWeek {
+		writeValidationError(w, r, "Invalid interval", invalidField("interval", "Must be day or week"))
+		return
+	}
+
+	// 預設查詢最近 30 天
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if value := params.Get("to"); value != "" {
+		to, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			writeValidationError(w, r, "Invalid to date", invalidField("to", "Must be a date in YYYY-MM-DD format"))
+			return
+		}
+	}
+	to = to.AddDate(0, 0, 1)
+
+	from := to.AddDate(0, 0, -defaultHistoryDays)
+	if value := params.Get("from"); value != "" {
+		from, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			writeValidationError(w, r, "Invalid from date", invalidField("from", "Must be a date in YYYY-MM-DD format"))
+			return
+		}
+	}
+
+	if !from.Before(to) {
+		writeValidationError(w, r, "from must not be after to", invalidField("from", "Must not be after to"))
+		return
+	}
+	if (interval == models.HistoryIntervalDay && from.AddDate(0, 0, maxHistoryDays).Before(to)) ||
+		(interval == models.HistoryIntervalWeek && from.AddDate(0, 0, maxHistoryWeeks*7).Before(to)) {
+		writeValidationError(w, r, "Date range too large", invalidField("from", "Date range too large"))
+		return
+	}
+
+	history, err := h.store.GetProgressHistory(r.Context(), currentOrgID(r), models.ProgressHistoryQuery{
+		UserID:   userID,
+		From:     from,
+		To:       to,
+		Interval: interval,
+	})
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetStrokeWeaknesses 獲取用戶各基本筆畫類型的平均得分與建議練習
+func (h *ProgressHandler) GetStrokeWeaknesses(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	weaknesses, err := analysis.StrokeTypeWeaknesses(r.Context(), h.store, currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(weaknesses)
+}
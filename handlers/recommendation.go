@@ -0,0 +1,66 @@
+// backend/handlers/recommendation.go
+package handlers
+
+import (
+	"backend/i18n"
+	"backend/recommendations"
+	"backend/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// 推薦筆數的預設與上限
+const (
+	defaultRecommendationLimit = 5
+	maxRecommendationLimit     = 50
+)
+
+// RecommendationHandler 處理練習推薦相關的請求
+type RecommendationHandler struct {
+	store       storage.Storage
+	recommender *recommendations.Recommender
+}
+
+// NewRecommendationHandler 創建一個新的推薦處理器
+func NewRecommendationHandler(store storage.Storage, recommender *recommendations.Recommender) *RecommendationHandler {
+	return &RecommendationHandler{
+		store:       store,
+		recommender: recommender,
+	}
+}
+
+// GetRecommendations 獲取用戶接下來應練習的字元
+func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	limit := defaultRecommendationLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxRecommendationLimit {
+			writeValidationError(w, r, "Invalid limit", invalidField("limit", i18n.T(r.Context(), "Must be between 1 and %d", maxRecommendationLimit)))
+			return
+		}
+	}
+
+	recommendations, err := h.recommender.Recommend(r.Context(), currentOrgID(r), userID, limit)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
@@ -2,25 +2,33 @@
 package handlers
 
 import (
+	"backend/achievements"
+	"backend/apierror"
+	"backend/i18n"
 	"backend/models"
 	"backend/storage"
 	"encoding/json"
+	"errors"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 // StrokeHandler 處理筆畫相關的請求
 type StrokeHandler struct {
-	store storage.Storage
+	store        storage.Storage
+	achievements *achievements.Engine
 }
 
 // NewStrokeHandler 創建一個新的筆畫處理器
-func NewStrokeHandler(store storage.Storage) *StrokeHandler {
+func NewStrokeHandler(store storage.Storage, engine *achievements.Engine) *StrokeHandler {
 	return &StrokeHandler{
-		store: store,
+		store:        store,
+		achievements: engine,
 	}
 }
 
@@ -28,13 +36,30 @@ func NewStrokeHandler(store storage.Storage) *StrokeHandler {
 func (h *StrokeHandler) RecordStroke(w http.ResponseWriter, r *http.Request) {
 	var req models.StrokeRecordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
 		return
 	}
 
 	// 驗證請求
-	if req.UserID <= 0 || req.CharacterID <= 0 || req.StrokeIndex < 0 || len(req.Path) < 2 {
-		http.Error(w, "Invalid request parameters", http.StatusBadRequest)
+	if details := validateStrokeRequest(req); len(details) > 0 {
+		writeValidationError(w, r, "Invalid request parameters", details...)
+		return
+	}
+
+	// 只能記錄自己的筆畫，老師與監護人只有唯讀權限
+	if !isSelf(r, req.UserID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	// 字元ID為全域編號，須確認是內建字元或本組織的自訂字元
+	exists, err := characterExists(h.store, r, req.CharacterID)
+	if err != nil {
+		writeStoreError(w, r, err, "Error saving stroke record")
+		return
+	}
+	if !exists {
+		writeValidationError(w, r, "Invalid request parameters", invalidField("characterId", "Character not found"))
 		return
 	}
 
@@ -47,45 +72,324 @@ func (h *StrokeHandler) RecordStroke(w http.ResponseWriter, r *http.Request) {
 		Score:       req.Score,
 	}
 
-	// 儲存記錄
-	record, err := h.store.CreateStrokeRecord(newRecord)
+	// 儲存記錄並更新進度、當日練習統計與成就，全部在同一交易中提交或回滾
+	var record *models.StrokeRecord
+	var unlocked []models.UserAchievement
+	err = h.store.RunInTx(r.Context(), func(tx storage.Storage) error {
+		var err error
+		record, err = tx.CreateStrokeRecord(r.Context(), newRecord)
+		if err != nil {
+			return err
+		}
+		if err := storage.ApplyStrokeRecord(r.Context(), tx, currentOrgID(r), *record); err != nil {
+			return err
+		}
+		unlocked, err = h.achievements.WithStore(tx).Evaluate(r.Context(), currentOrgID(r), req.UserID, *record)
+		return err
+	})
 	if err != nil {
-		http.Error(w, "Error saving stroke record", http.StatusInternalServerError)
+		writeStoreError(w, r, err, "Error saving stroke record")
 		return
 	}
 
 	// 簡化筆畫路徑為關鍵節點
 	simplifiedNodes := h.simplifyStroke(req.Path)
 
-	// 更新用戶進度
-	err = h.store.UpdateUserProgress(req.UserID, req.CharacterID, req.StrokeIndex, req.Score)
+	// 返回成功響應
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.StrokeRecordResponse{
+		RecordID:             record.ID,
+		SimplifiedNodes:      simplifiedNodes,
+		UnlockedAchievements: unlocked,
+	})
+}
+
+// maxBatchRecords 單次批次同步的筆數上限
+const maxBatchRecords = 500
+
+// RecordStrokeBatch 批次同步離線練習的筆畫記錄
+// 以客戶端ID去重，依客戶端時間順序套用到進度，並逐筆返回處理結果
+func (h *StrokeHandler) RecordStrokeBatch(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchStrokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	var details []apierror.FieldError
+	if req.UserID <= 0 {
+		details = append(details, invalidField("userId", "Must be a positive ID"))
+	}
+	if len(req.Records) == 0 {
+		details = append(details, invalidField("records", "At least one record is required"))
+	}
+	if len(details) > 0 {
+		writeValidationError(w, r, "Invalid request parameters", details...)
+		return
+	}
+	if len(req.Records) > maxBatchRecords {
+		writeError(w, r, http.StatusRequestEntityTooLarge, "Too many records in batch")
+		return
+	}
+
+	// 只能同步自己的筆畫
+	if !isSelf(r, req.UserID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	// 依客戶端時間排序處理，結果仍按請求順序返回
+	now := time.Now()
+	practicedAt := make([]time.Time, len(req.Records))
+	order := make([]int, len(req.Records))
+	for i, item := range req.Records {
+		practicedAt[i] = clientTime(item.ClientTimestamp, now)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return practicedAt[order[i]].Before(practicedAt[order[j]])
+	})
+
+	// 整批記錄、進度與成就在同一交易中提交，任一步失敗時整批回滾，客戶端可安全重送
+	results := make([]models.BatchStrokeResult, len(req.Records))
+	var unlocked []models.UserAchievement
+	err := h.store.RunInTx(r.Context(), func(tx storage.Storage) error {
+		seen := make(map[string]int)     // clientID -> 本批次建立的記錄ID
+		characters := make(map[int]bool) // characterID -> 是否可練習
+		var last *models.StrokeRecord
+		for _, i := range order {
+			item := req.Records[i]
+			result := models.BatchStrokeResult{ClientID: item.ClientID}
+
+			switch {
+			case item.ClientID == "":
+				result.Status = models.BatchItemInvalid
+				result.Error = i18n.T(r.Context(), "missing client ID")
+			case item.CharacterID <= 0 || item.StrokeIndex < 0 || len(item.Path) < 2:
+				result.Status = models.BatchItemInvalid
+				result.Error = i18n.T(r.Context(), "invalid stroke parameters")
+			case seen[item.ClientID] > 0:
+				result.Status = models.BatchItemDuplicate
+				result.RecordID = seen[item.ClientID]
+			default:
+				exists, checked := characters[item.CharacterID]
+				if !checked {
+					var err error
+					if exists, err = characterExists(tx, r, item.CharacterID); err != nil {
+						return err
+					}
+					characters[item.CharacterID] = exists
+				}
+				if !exists {
+					result.Status = models.BatchItemInvalid
+					result.Error = i18n.T(r.Context(), "unknown character")
+					break
+				}
+
+				existing, err := tx.GetStrokeRecordByClientID(r.Context(), currentOrgID(r), req.UserID, item.ClientID)
+				if err == nil {
+					result.Status = models.BatchItemDuplicate
+					result.RecordID = existing.ID
+					break
+				}
+				if !errors.Is(err, storage.ErrNotFound) {
+					return err
+				}
+
+				record, err := tx.CreateStrokeRecord(r.Context(), models.StrokeRecord{
+					UserID:      req.UserID,
+					CharacterID: item.CharacterID,
+					StrokeIndex: item.StrokeIndex,
+					Path:        item.Path,
+					Score:       item.Score,
+					ClientID:    item.ClientID,
+					CreatedAt:   practicedAt[i],
+				})
+				if err != nil {
+					return err
+				}
+				if err := storage.ApplyStrokeRecord(r.Context(), tx, currentOrgID(r), *record); err != nil {
+					return err
+				}
+				result.Status = models.BatchItemCreated
+				result.RecordID = record.ID
+				seen[item.ClientID] = record.ID
+				last = record
+			}
+
+			results[i] = result
+		}
+
+		// 同步完成後以最後一筆新記錄評估成就
+		if last == nil {
+			return nil
+		}
+		var err error
+		unlocked, err = h.achievements.WithStore(tx).Evaluate(r.Context(), currentOrgID(r), req.UserID, *last)
+		return err
+	})
 	if err != nil {
-		http.Error(w, "Error updating user progress", http.StatusInternalServerError)
+		writeStoreError(w, r, err, "Error saving stroke records")
 		return
 	}
 
-	// 返回成功響應
+	response := models.BatchStrokeResponse{
+		Results:              results,
+		UnlockedAchievements: unlocked,
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(models.StrokeRecordResponse{
-		RecordID:        record.ID,
-		SimplifiedNodes: simplifiedNodes,
-	})
+	json.NewEncoder(w).Encode(response)
+}
+
+// validateStrokeRequest 驗證單筆筆畫請求，返回各欄位的錯誤
+func validateStrokeRequest(req models.StrokeRecordRequest) []apierror.FieldError {
+	var details []apierror.FieldError
+	if req.UserID <= 0 {
+		details = append(details, invalidField("userId", "Must be a positive ID"))
+	}
+	if req.CharacterID <= 0 {
+		details = append(details, invalidField("characterId", "Must be a positive ID"))
+	}
+	if req.StrokeIndex < 0 {
+		details = append(details, invalidField("strokeIndex", "Must not be negative"))
+	}
+	if len(req.Path) < 2 {
+		details = append(details, invalidField("path", "At least two points are required"))
+	}
+	return details
+}
+
+// characterExists 確認字元是內建字元或已認證用戶組織的自訂字元
+func characterExists(store storage.Storage, r *http.Request, characterID int) (bool, error) {
+	_, err := store.GetCharacterByID(r.Context(), currentOrgID(r), characterID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
-// GetUserStrokeRecords 獲取用戶筆畫記錄
+// clientTime 返回記錄的練習時間，缺少或晚於伺服器時間的客戶端時間以當前時間代替
+func clientTime(timestamp, now time.Time) time.Time {
+	if timestamp.IsZero() || timestamp.After(now) {
+		return now
+	}
+	return timestamp
+}
+
+// 筆畫記錄每頁筆數的預設與上限
+const (
+	defaultRecordPageSize = 100
+	maxRecordPageSize     = 1000
+)
+
+// GetUserStrokeRecords 獲取用戶筆畫記錄，指定 cursor 或 limit 時分頁返回
+// 查詢參數: characterId、strokeIndex、from、to（RFC 3339）、minScore、maxScore、cursor、limit
 func (h *StrokeHandler) GetUserStrokeRecords(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := strconv.Atoi(vars["userId"])
 	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 檢查查看權限
+	if !authorizeUserRead(w, r, h.store, userID) {
+		return
+	}
+
+	query, ok := parseStrokeRecordQuery(w, r, userID)
+	if !ok {
 		return
 	}
 
 	// 獲取用戶記錄
-	records := h.store.GetStrokeRecordsByUserID(userID)
+	page, err := h.store.QueryStrokeRecords(r.Context(), currentOrgID(r), query)
+	if err != nil {
+		writeStoreError(w, r, err, "Error fetching stroke records")
+		return
+	}
+
+	writeStrokeRecords(w, query, page)
+}
 
+// writeStrokeRecords 寫入筆畫記錄查詢結果
+// 未分頁的查詢沿用舊版的回應格式，直接返回記錄陣列
+func writeStrokeRecords(w http.ResponseWriter, query models.StrokeRecordQuery, page *models.StrokeRecordPage) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(records)
+	if query.Limit == 0 {
+		json.NewEncoder(w).Encode(page.Records)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseStrokeRecordQuery 解析筆畫記錄的分頁與篩選參數
+// 只有指定 cursor 或 limit 時才分頁，否則 Limit 為 0 表示返回所有符合條件的記錄
+func parseStrokeRecordQuery(w http.ResponseWriter, r *http.Request, userID int) (models.StrokeRecordQuery, bool) {
+	params := r.URL.Query()
+	query := models.StrokeRecordQuery{
+		UserID: userID,
+		Cursor: params.Get("cursor"),
+	}
+	if query.Cursor != "" {
+		if _, err := storage.DecodeCursor(query.Cursor); err != nil {
+			writeValidationError(w, r, "Invalid cursor", invalidField("cursor", "Invalid value"))
+			return query, false
+		}
+		query.Limit = defaultRecordPageSize
+	}
+
+	intParam := func(name string, target **int) bool {
+		if value := params.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				writeValidationError(w, r, i18n.T(r.Context(), "Invalid %s", name), invalidField(name, "Invalid value"))
+				return false
+			}
+			*target = &n
+		}
+		return true
+	}
+	scoreParam := func(name string, target **float64) bool {
+		if value := params.Get(name); value != "" {
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				writeValidationError(w, r, i18n.T(r.Context(), "Invalid %s", name), invalidField(name, "Invalid value"))
+				return false
+			}
+			*target = &score
+		}
+		return true
+	}
+	timeParam := func(name string, target *time.Time) bool {
+		if value := params.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeValidationError(w, r, i18n.T(r.Context(), "Invalid %s", name), invalidField(name, "Invalid value"))
+				return false
+			}
+			*target = t
+		}
+		return true
+	}
+
+	if !intParam("characterId", &query.CharacterID) || !intParam("strokeIndex", &query.StrokeIndex) ||
+		!scoreParam("minScore", &query.MinScore) || !scoreParam("maxScore", &query.MaxScore) ||
+		!timeParam("from", &query.From) || !timeParam("to", &query.To) {
+		return query, false
+	}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxRecordPageSize {
+			writeValidationError(w, r, "Invalid limit",
+				invalidField("limit", i18n.T(r.Context(), "Must be between 1 and %d", maxRecordPageSize)))
+			return query, false
+		}
+		query.Limit = limit
+	}
+
+	return query, true
 }
 
 // simplifyStroke 簡化筆畫路徑為關鍵節點
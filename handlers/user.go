@@ -0,0 +1,268 @@
+// backend/handlers/user.go
+package handlers
+
+import (
+	"backend/accounts"
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// UserHandler 處理用戶帳號相關的請求
+type UserHandler struct {
+	store   storage.Storage
+	locales *i18n.Bundle
+}
+
+// NewUserHandler 創建一個新的用戶處理器
+func NewUserHandler(store storage.Storage, locales *i18n.Bundle) *UserHandler {
+	return &UserHandler{
+		store:   store,
+		locales: locales,
+	}
+}
+
+// UpdatePrivacy 更新用戶隱私設定
+func (h *UserHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 只能修改自己的設定
+	if !isSelf(r, userID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	var settings models.PrivacySettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+
+	user, err := h.store.GetUserByID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	user.LeaderboardOptOut = settings.LeaderboardOptOut
+	if _, err := h.store.UpdateUser(r.Context(), *user); err != nil {
+		writeStoreError(w, r, err, "Error updating user")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdatePreferences 更新用戶偏好設定
+func (h *UserHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	// 只能修改自己的設定
+	if !isSelf(r, userID) {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	var settings models.PreferenceSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if settings.Locale != "" && !h.locales.Supports(settings.Locale) {
+		writeValidationError(w, r, "Unsupported locale",
+			invalidField("locale", i18n.T(r.Context(), "Must be one of %s", strings.Join(h.locales.Locales(), ", "))))
+		return
+	}
+
+	user, err := h.store.GetUserByID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return
+	}
+
+	user.Locale = settings.Locale
+	if _, err := h.store.UpdateUser(r.Context(), *user); err != nil {
+		writeStoreError(w, r, err, "Error updating user")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// loadManagedUser 載入已認證用戶可以管理帳號的組織用戶：本人或組織管理員
+func (h *UserHandler) loadManagedUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["userId"])
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid user ID")
+		return nil, false
+	}
+
+	if !isSelf(r, userID) && currentRole(r) != models.RoleAdmin {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return nil, false
+	}
+
+	user, err := h.store.GetUserByID(r.Context(), currentOrgID(r), userID)
+	if err != nil {
+		writeStoreError(w, r, err, "User not found")
+		return nil, false
+	}
+	if user.DeletedAt != nil {
+		writeError(w, r, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+	return user, true
+}
+
+// DeleteAccount 申請刪除帳號，寬限期後清除資料
+// 管理員可使用 immediate=true 立即清除
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadManagedUser(w, r)
+	if !ok {
+		return
+	}
+	actorID, _ := currentUserID(r)
+
+	if r.URL.Query().Get("immediate") == "true" {
+		if currentRole(r) != models.RoleAdmin {
+			writeError(w, r, http.StatusForbidden, "Forbidden")
+			return
+		}
+		if err := accounts.Purge(r.Context(), h.store, actorID, *user, time.Now()); err != nil {
+			writeAccountError(w, r, err, "Error deleting user")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	updated, err := accounts.RequestDeletion(r.Context(), h.store, actorID, *user, time.Now())
+	if err != nil {
+		writeAccountError(w, r, err, "Error requesting account deletion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(updated.DeletionStatus())
+}
+
+// CancelAccountDeletion 在寬限期內取消刪除帳號的申請
+func (h *UserHandler) CancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.loadManagedUser(w, r)
+	if !ok {
+		return
+	}
+	actorID, _ := currentUserID(r)
+
+	updated, err := accounts.CancelDeletion(r.Context(), h.store, actorID, *user, time.Now())
+	if err != nil {
+		writeAccountError(w, r, err, "Error cancelling account deletion")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// writeAccountError 回應帳號刪除相關的錯誤，帳號已清除時回應 409
+func writeAccountError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if errors.Is(err, accounts.ErrAlreadyDeleted) {
+		writeError(w, r, http.StatusConflict, "Account already deleted")
+		return
+	}
+	writeStoreError(w, r, err, message)
+}
+
+// UpdateRole 變更組織用戶的角色，僅限管理員，只能在學生與老師之間變更
+func (h *UserHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	if currentRole(r) != models.RoleAdmin {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+	user, ok := h.loadManagedUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RoleUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "Invalid request format")
+		return
+	}
+	if req.Role != models.RoleStudent && req.Role != models.RoleTeacher {
+		writeValidationError(w, r, "Invalid role", invalidField("role", "Must be student or teacher"))
+		return
+	}
+	if user.Role == models.RoleAdmin {
+		writeError(w, r, http.StatusConflict, "Admin roles cannot be changed")
+		return
+	}
+
+	// 角色與稽核記錄在同一交易中寫入
+	if user.Role != req.Role {
+		actorID, _ := currentUserID(r)
+		user.Role = req.Role
+		err := h.store.RunInTx(r.Context(), func(tx storage.Storage) error {
+			if _, err := tx.UpdateUser(r.Context(), *user); err != nil {
+				return err
+			}
+			_, err := tx.CreateAuditEntry(r.Context(), models.AuditEntry{
+				OrgID:        user.OrgID,
+				ActorID:      actorID,
+				Action:       models.AuditRoleChanged,
+				TargetUserID: user.ID,
+				CreatedAt:    time.Now(),
+			})
+			return err
+		})
+		if err != nil {
+			writeStoreError(w, r, err, "Error updating user")
+			return
+		}
+	}
+
+	// 隱藏密碼
+	user.Password = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetAuditLog 獲取組織的稽核記錄，僅限管理員
+func (h *UserHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if currentRole(r) != models.RoleAdmin {
+		writeError(w, r, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	entries, err := h.store.GetAuditEntries(r.Context(), currentOrgID(r))
+	if err != nil {
+		writeStoreError(w, r, err, "Organization not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,133 @@
+// backend/i18n/i18n.go
+
+// Package i18n 依語系翻譯 API 回應中的文字
+//
+// 訊息目錄為每個語系一個 JSON 檔（例如 zh-TW.json），內容是鍵到譯文的對照。
+// 錯誤訊息與練習提示以英文原文為鍵，可含 fmt 格式符號；成就等資料以 "achievement.<id>.name" 形式的鍵翻譯。
+// 目錄中沒有的鍵以原文輸出。
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed locales/*.json
+var embedded embed.FS
+
+// Bundle 所有語系的訊息目錄
+type Bundle struct {
+	defaultLocale string
+	catalogs      map[string]map[string]string // 語系 -> 鍵 -> 譯文
+}
+
+// LoadDefault 載入隨程式內建的訊息目錄
+func LoadDefault(defaultLocale string) (*Bundle, error) {
+	locales, err := fs.Sub(embedded, "locales")
+	if err != nil {
+		return nil, err
+	}
+	return Load(locales, defaultLocale)
+}
+
+// Load 載入目錄下所有 <語系>.json 訊息目錄；defaultLocale 用於客戶端未指定或不支援的語系
+func Load(fsys fs.FS, defaultLocale string) (*Bundle, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{defaultLocale: defaultLocale, catalogs: make(map[string]map[string]string)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		b.catalogs[strings.TrimSuffix(path.Base(file), ".json")] = catalog
+	}
+
+	if _, ok := b.catalogs[defaultLocale]; !ok {
+		return nil, fmt.Errorf("no message catalog for default locale %q", defaultLocale)
+	}
+	return b, nil
+}
+
+// Locales 返回所有支援的語系，依名稱排序
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supports 判斷是否支援指定語系
+func (b *Bundle) Supports(locale string) bool {
+	_, ok := b.catalogs[locale]
+	return ok
+}
+
+// Localizer 指定語系的翻譯器
+type Localizer struct {
+	Locale  string
+	catalog map[string]string
+}
+
+// Localizer 返回指定語系的翻譯器，不支援的語系使用預設語系
+func (b *Bundle) Localizer(locale string) *Localizer {
+	if !b.Supports(locale) {
+		locale = b.defaultLocale
+	}
+	return &Localizer{Locale: locale, catalog: b.catalogs[locale]}
+}
+
+// Lookup 返回鍵的譯文，目錄中沒有時返回 false
+func (l *Localizer) Lookup(key string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	message, ok := l.catalog[key]
+	return message, ok
+}
+
+// T 翻譯訊息並代入參數，目錄中沒有時以鍵本身作為格式
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message, ok := l.Lookup(key)
+	if !ok {
+		message = key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// 創建上下文鍵類型
+type contextKey struct{}
+
+// WithLocalizer 將翻譯器加入上下文
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 從上下文中獲取翻譯器，未設定時返回 nil，翻譯時輸出原文
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(contextKey{}).(*Localizer)
+	return l
+}
+
+// T 以上下文中的翻譯器翻譯訊息
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return FromContext(ctx).T(key, args...)
+}
@@ -0,0 +1,95 @@
+// backend/i18n/negotiate.go
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// chineseScripts 中文地區與書寫系統對應的語系
+var chineseScripts = map[string]string{
+	"hant": "zh-TW",
+	"tw":   "zh-TW",
+	"hk":   "zh-TW",
+	"mo":   "zh-TW",
+	"hans": "zh-CN",
+	"cn":   "zh-CN",
+	"sg":   "zh-CN",
+	"my":   "zh-CN",
+}
+
+// Negotiate 依 Accept-Language 標頭選擇支援的語系，沒有相符的語系時返回預設語系
+// 依權重由高到低比對，先比對完整標籤，再以語言與地區或書寫系統推斷，例如 zh-Hant-HK 對應 zh-TW。
+func (b *Bundle) Negotiate(header string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, p := range preferences {
+		if locale := b.match(p.tag); locale != "" {
+			return locale
+		}
+	}
+	return b.defaultLocale
+}
+
+// match 將語言標籤對應到支援的語系，無法對應時返回空字串
+func (b *Bundle) match(tag string) string {
+	if tag == "*" {
+		return b.defaultLocale
+	}
+	for locale := range b.catalogs {
+		if strings.EqualFold(locale, tag) {
+			return locale
+		}
+	}
+
+	subtags := strings.Split(strings.ToLower(tag), "-")
+	if subtags[0] == "zh" {
+		for _, subtag := range subtags[1:] {
+			if locale, ok := chineseScripts[subtag]; ok && b.Supports(locale) {
+				return locale
+			}
+		}
+		// 未標示地區的中文使用預設語系，預設語系不是中文時使用繁體中文
+		if strings.HasPrefix(b.defaultLocale, "zh") {
+			return b.defaultLocale
+		}
+		if b.Supports("zh-TW") {
+			return "zh-TW"
+		}
+	}
+
+	// 只比對語言，例如 en-US 對應 en
+	for _, locale := range b.Locales() {
+		if strings.EqualFold(strings.Split(locale, "-")[0], subtags[0]) {
+			return locale
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+// backend/i18n/negotiate_test.go
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	bundle, err := LoadDefault("en")
+	if err != nil {
+		t.Fatalf("LoadDefault: %v", err)
+	}
+
+	tests := []struct {
+		name, header, want string
+	}{
+		{"exact tag", "zh-TW", "zh-TW"},
+		{"case insensitive", "ZH-cn", "zh-CN"},
+		{"highest quality first", "en;q=0.5, zh-CN;q=0.9, zh-TW;q=0.7", "zh-CN"},
+		{"order breaks quality ties", "zh-TW;q=0.8, zh-CN;q=0.8", "zh-TW"},
+		{"zero quality is refused", "zh-CN;q=0, en;q=0.1", "en"},
+		{"traditional script and region", "zh-Hant-HK", "zh-TW"},
+		{"hong kong region", "zh-HK", "zh-TW"},
+		{"singapore region", "zh-SG", "zh-CN"},
+		{"simplified script", "zh-Hans", "zh-CN"},
+		{"language only", "en-US", "en"},
+		{"unsupported language", "fr-FR, de;q=0.8", "en"},
+		{"wildcard", "fr, *;q=0.5", "en"},
+		{"empty header", "", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bundle.Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateChineseWithoutRegion(t *testing.T) {
+	// 未標示地區的中文在預設語系為中文時使用預設語系，否則使用繁體中文
+	for defaultLocale, want := range map[string]string{"en": "zh-TW", "zh-CN": "zh-CN"} {
+		bundle, err := LoadDefault(defaultLocale)
+		if err != nil {
+			t.Fatalf("LoadDefault(%q): %v", defaultLocale, err)
+		}
+		if got := bundle.Negotiate("zh"); got != want {
+			t.Errorf("default %s: Negotiate(\"zh\") = %q, want %q", defaultLocale, got, want)
+		}
+	}
+}
@@ -3,10 +3,16 @@ package main
 
 import (
 	"backend/configs"
+	"backend/i18n"
+	"backend/middleware"
 	"backend/routes"
+	"backend/storage/cache"
+	"backend/storage/memory"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/rs/cors"
@@ -16,21 +22,61 @@ func main() {
 	// 加載配置
 	config := configs.LoadConfig()
 
-	// 設置路由
-	router := routes.SetupRoutes(config)
+	// 初始化儲存，設定資料目錄時從快照與日誌還原
+	store := memory.NewMemoryStorage()
+	if config.DataDir != "" {
+		var err error
+		store, err = memory.Open(config.DataDir)
+		if err != nil {
+			log.Fatalf("Error opening data directory: %v", err)
+		}
+		defer store.Close()
+	}
+
+	// 執行命令列子命令
+	if len(os.Args) > 1 {
+		if err := runCommand(context.Background(), store, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// 定期清除寬限期已結束的帳號
+	go purgeDeletedAccounts(store, time.Hour)
+
+	// 定期寫入快照以縮短重啟時需重播的日誌
+	if config.DataDir != "" {
+		go snapshotPeriodically(store, config.SnapshotInterval)
+	}
+
+	// 載入訊息目錄，設定目錄時以檔案取代內建的目錄
+	var locales *i18n.Bundle
+	var err error
+	if config.LocalesDir != "" {
+		locales, err = i18n.Load(os.DirFS(config.LocalesDir), config.DefaultLocale)
+	} else {
+		locales, err = i18n.LoadDefault(config.DefaultLocale)
+	}
+	if err != nil {
+		log.Fatalf("Error loading message catalogs: %v", err)
+	}
+
+	// 設置路由，字元資料經由快取讀取
+	router := routes.SetupRoutes(config, cache.New(store, config.CharacterCacheTTL), locales)
 
 	// 設置 CORS
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   config.AllowedOrigins,
 		AllowedMethods:   config.AllowedMethods,
 		AllowedHeaders:   config.AllowedHeaders,
+		ExposedHeaders:   []string{"ETag", "Content-Language", middleware.RequestIDHeader},
 		AllowCredentials: config.AllowCredentials,
 		MaxAge:           300, // 5 分鐘的預檢快取
 	})
 
 	// 設置服務器
 	server := &http.Server{
-		Handler:      corsHandler.Handler(router),
+		Handler:      corsHandler.Handler(middleware.RequestIDMiddleware(middleware.LocaleMiddleware(locales)(router))),
 		Addr:         ":" + config.Port,
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
@@ -2,7 +2,10 @@
 package middleware
 
 import (
+	"backend/apierror"
 	"backend/configs"
+	"backend/i18n"
+	"backend/models"
 	"context"
 	"fmt"
 	"net/http"
@@ -14,7 +17,12 @@ import (
 // 創建上下文鍵類型
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey contextKey = "userID"
+	roleKey   contextKey = "role"
+	orgIDKey  contextKey = "orgID"
+	userKey   contextKey = "user"
+)
 
 // GetUserIDFromContext 從上下文中獲取用戶ID
 func GetUserIDFromContext(ctx context.Context) (interface{}, bool) {
@@ -22,6 +30,18 @@ func GetUserIDFromContext(ctx context.Context) (interface{}, bool) {
 	return userID, ok
 }
 
+// GetRoleFromContext 從上下文中獲取用戶角色
+func GetRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(roleKey).(string)
+	return role, ok
+}
+
+// GetOrgIDFromContext 從上下文中獲取用戶所屬的組織ID
+func GetOrgIDFromContext(ctx context.Context) (int, bool) {
+	orgID, ok := ctx.Value(orgIDKey).(int)
+	return orgID, ok
+}
+
 // AuthMiddleware 認證中間件
 func AuthMiddleware(config *configs.Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -41,7 +61,7 @@ func AuthMiddleware(config *configs.Config) func(http.Handler) http.Handler {
 			// 從請求標頭獲取 JWT Token
 			tokenString := r.Header.Get("Authorization")
 			if tokenString == "" {
-				http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: No token provided"))
 				return
 			}
 
@@ -60,26 +80,40 @@ func AuthMiddleware(config *configs.Config) func(http.Handler) http.Handler {
 			})
 
 			if err != nil || !token.Valid {
-				http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: Invalid token"))
 				return
 			}
 
 			// Token 有效，取得 claims
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, "Unauthorized: Invalid token claims", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: Invalid token claims"))
 				return
 			}
 
 			// 從 claims 獲取用戶 ID
 			userID, ok := claims["user_id"]
 			if !ok {
-				http.Error(w, "Unauthorized: User ID not found in token", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: User ID not found in token"))
 				return
 			}
 
-			// 將用戶 ID 添加到上下文
+			// 舊的 Token 沒有角色，視為學生
+			role, ok := claims["role"].(string)
+			if !ok {
+				role = models.RoleStudent
+			}
+
+			// 舊的 Token 沒有組織，視為預設組織
+			orgID := models.DefaultOrganizationID
+			if value, ok := claims["org_id"].(float64); ok {
+				orgID = int(value)
+			}
+
+			// 將用戶 ID、角色與組織添加到上下文
 			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			ctx = context.WithValue(ctx, roleKey, role)
+			ctx = context.WithValue(ctx, orgIDKey, orgID)
 			r = r.WithContext(ctx)
 
 			// 繼續執行下一個處理程序
@@ -0,0 +1,33 @@
+// backend/middleware/locale.go
+package middleware
+
+import (
+	"backend/i18n"
+	"net/http"
+)
+
+// LocaleMiddleware 依 Accept-Language 選擇回應的語系
+func LocaleMiddleware(bundle *i18n.Bundle) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			localizer := bundle.Localizer(bundle.Negotiate(r.Header.Get("Accept-Language")))
+			w.Header().Add("Vary", "Accept-Language")
+			w.Header().Set("Content-Language", localizer.Locale)
+			next.ServeHTTP(w, r.WithContext(i18n.WithLocalizer(r.Context(), localizer)))
+		})
+	}
+}
+
+// UserLocaleMiddleware 已認證用戶設定了偏好語系時優先使用，須在 ActiveUserMiddleware 之後執行
+func UserLocaleMiddleware(bundle *i18n.Bundle) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if ok && user.Locale != "" && bundle.Supports(user.Locale) {
+				w.Header().Set("Content-Language", user.Locale)
+				r = r.WithContext(i18n.WithLocalizer(r.Context(), bundle.Localizer(user.Locale)))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// backend/middleware/requestid.go
+package middleware
+
+import (
+	"backend/apierror"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader 傳遞請求追蹤ID的標頭
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength 沿用客戶端追蹤ID的長度上限
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware 為每個請求指定追蹤ID
+// 客戶端或代理已提供有效的 X-Request-ID 時沿用，否則隨機產生；追蹤ID會回傳在回應標頭與錯誤回應中。
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(apierror.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID 只接受長度有限的英數字、連字號、底線與句點，避免寫入日誌時被注入內容
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID 產生 16 位元組的隨機追蹤ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,48 @@
+// backend/middleware/user.go
+package middleware
+
+import (
+	"backend/apierror"
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// GetUserFromContext 從上下文中獲取已認證用戶的帳號資料
+func GetUserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userKey).(*models.User)
+	return user, ok
+}
+
+// ActiveUserMiddleware 確認 Token 的用戶仍存在且帳號未被清除，須在 AuthMiddleware 之後執行
+// 帳號清除後 Token 在到期前仍可通過簽章驗證，需在此拒絕；申請刪除但仍在寬限期內的帳號可繼續使用。
+func ActiveUserMiddleware(store storage.Storage) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, _ := GetUserIDFromContext(r.Context())
+			orgID, _ := GetOrgIDFromContext(r.Context())
+			id, ok := userID.(float64)
+			if !ok {
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: Invalid token claims"))
+				return
+			}
+
+			user, err := store.GetUserByID(r.Context(), orgID, int(id))
+			switch {
+			case errors.Is(err, storage.ErrNotFound) || err == nil && user.DeletedAt != nil:
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, i18n.T(r.Context(), "Unauthorized: Account no longer exists"))
+				return
+			case err != nil:
+				log.Printf("request %s: storage error: %v", apierror.RequestIDFromContext(r.Context()), err)
+				apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, i18n.T(r.Context(), "Internal server error"))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey, user)))
+		})
+	}
+}
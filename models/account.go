@@ -0,0 +1,51 @@
+// backend/models/account.go
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// AccountDeletionGracePeriod 申請刪除帳號後保留資料、可取消申請的期間
+	AccountDeletionGracePeriod = 14 * 24 * time.Hour
+	// DeletedUsernamePrefix 匿名化帳號的用戶名前綴，保留給系統使用
+	DeletedUsernamePrefix = "deleted-user-"
+)
+
+// IsReservedUsername 判斷用戶名是否使用系統保留的前綴
+func IsReservedUsername(username string) bool {
+	return strings.HasPrefix(strings.ToLower(username), DeletedUsernamePrefix)
+}
+
+// AnonymizedUsername 返回匿名化帳號的用戶名，attempt 大於 1 時加上序號以避開重複的用戶名
+func AnonymizedUsername(userID, attempt int) string {
+	if attempt <= 1 {
+		return fmt.Sprintf("%s%d", DeletedUsernamePrefix, userID)
+	}
+	return fmt.Sprintf("%s%d-%d", DeletedUsernamePrefix, userID, attempt)
+}
+
+// DeletionStatus 返回帳號刪除申請狀態，未申請刪除時返回 nil
+func (u User) DeletionStatus() *AccountDeletionStatus {
+	if u.DeletionRequestedAt == nil {
+		return nil
+	}
+	return &AccountDeletionStatus{
+		UserID:      u.ID,
+		RequestedAt: *u.DeletionRequestedAt,
+		PurgeAt:     u.DeletionRequestedAt.Add(AccountDeletionGracePeriod),
+	}
+}
+
+// Anonymize 清除可識別個人的帳號資料並改用指定的匿名用戶名，保留ID以維持班級等記錄的關聯
+func (u *User) Anonymize(username string, at time.Time) {
+	u.Username = username
+	u.Password = ""
+	u.Email = ""
+	u.LeaderboardOptOut = true
+	u.Locale = ""
+	u.DeletionRequestedAt = nil
+	u.DeletedAt = &at
+}
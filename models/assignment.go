@@ -0,0 +1,50 @@
+// backend/models/assignment.go
+package models
+
+import "time"
+
+// StatusFor 依學生的練習進度計算作業完成狀態
+// 作業要求的是學生的實際程度，因此以最近得分的移動平均判斷，不套用時間衰減。
+// 到期後以 dueProgress 中保存的到期進度為準，之後的練習不再改變作業狀態；
+// 尚未保存表示到期後還沒有練習，目前的進度即為到期時的進度。
+func (a Assignment) StatusFor(userID int, progress UserProgress, dueProgress map[int]UserProgress, now time.Time) AssignmentStudentStatus {
+	if saved, exists := dueProgress[userID]; exists && now.After(a.DueAt) {
+		progress = saved
+	}
+
+	status := AssignmentStudentStatus{
+		UserID:          userID,
+		TotalCharacters: len(a.CharacterIDs),
+		Characters:      make([]AssignmentCharacterStatus, 0, len(a.CharacterIDs)),
+	}
+
+	attempted := false
+	for _, characterID := range a.CharacterIDs {
+		charProgress := progress[characterID]
+		characterStatus := AssignmentCharacterStatus{
+			CharacterID: characterID,
+			Attempts:    charProgress.Attempts,
+			Mastery:     charProgress.RecentScore * 100,
+		}
+		characterStatus.Completed = charProgress.Attempts > 0 && characterStatus.Mastery >= a.MasteryThreshold
+		if characterStatus.Completed {
+			status.CompletedCharacters++
+		}
+		if charProgress.Attempts > 0 {
+			attempted = true
+		}
+		status.Characters = append(status.Characters, characterStatus)
+	}
+
+	switch {
+	case status.CompletedCharacters == status.TotalCharacters:
+		status.Status = AssignmentCompleted
+	case now.After(a.DueAt):
+		status.Status = AssignmentOverdue
+	case attempted:
+		status.Status = AssignmentInProgress
+	default:
+		status.Status = AssignmentNotStarted
+	}
+	return status
+}
@@ -0,0 +1,42 @@
+// backend/models/leaderboard.go
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinAccuracyStrokes 參與準確度排名所需的最少筆畫數
+const MinAccuracyStrokes = 20
+
+// Periods 所有排行榜統計期間
+var Periods = []LeaderboardPeriod{LeaderboardWeekly, LeaderboardMonthly, LeaderboardAllTime}
+
+// PeriodKey 返回時間所屬的期間鍵，例如 week:2026-W42、month:2026-10、all
+func PeriodKey(period LeaderboardPeriod, t time.Time) string {
+	t = t.UTC()
+	switch period {
+	case LeaderboardWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("week:%d-W%02d", year, week)
+	case LeaderboardMonthly:
+		return fmt.Sprintf("month:%s", t.Format("2006-01"))
+	}
+	return "all"
+}
+
+// Value 依排名依據返回彙總的數值，第二個返回值表示是否有資格排名
+func (a PracticeAggregate) Value(metric LeaderboardMetric) (float64, bool) {
+	switch metric {
+	case MetricMastered:
+		return float64(a.MasteredCharacters), a.MasteredCharacters > 0
+	case MetricAccuracy:
+		if a.Strokes < MinAccuracyStrokes {
+			return 0, false
+		}
+		return a.ScoreSum / float64(a.Strokes), true
+	case MetricVolume:
+		return float64(a.Strokes), a.Strokes > 0
+	}
+	return 0, false
+}
@@ -0,0 +1,73 @@
+// backend/models/mastery.go
+package models
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MasteryEMAWeight 最新一次得分在指數移動平均中的權重
+	MasteryEMAWeight = 0.3
+	// MasteryHalfLife 停止練習後熟練度衰減一半所需的時間
+	MasteryHalfLife = 30 * 24 * time.Hour
+	// MasteryThreshold 熟練度達到此值即視為已掌握
+	MasteryThreshold = 80.0
+	// WeakStrokeScore 筆畫平均得分低於此值視為弱項
+	WeakStrokeScore = 0.6
+)
+
+// ApplyScore 將一次筆畫得分併入字元進度
+// 離線同步的得分若早於上次練習，只計入次數與平均，不影響近期得分與熟練度
+func (p *CharacterProgress) ApplyScore(strokeIndex int, score float64, at time.Time) {
+	stale := p.Attempts > 0 && at.Before(p.LastPracticedAt)
+
+	if p.Attempts == 0 {
+		p.RecentScore = score
+		p.LastStroke = strokeIndex
+	} else {
+		// 越近的得分權重越高
+		if !stale {
+			p.RecentScore = MasteryEMAWeight*score + (1-MasteryEMAWeight)*p.RecentScore
+		}
+
+		// 更新最後筆畫索引（如果更大）
+		if strokeIndex > p.LastStroke {
+			p.LastStroke = strokeIndex
+		}
+	}
+
+	p.AvgScore = (p.AvgScore*float64(p.Attempts) + score) / float64(p.Attempts+1)
+	p.Attempts++
+	if stale {
+		return
+	}
+
+	p.LastPracticedAt = at
+	p.Mastery = p.MasteryAt(at)
+
+	if p.MasteredAt == nil && p.Mastery >= MasteryThreshold {
+		masteredAt := at
+		p.MasteredAt = &masteredAt
+	}
+}
+
+// MasteryAt 計算指定時間點的熟練度（0-100），距離上次練習越久衰減越多
+func (p CharacterProgress) MasteryAt(now time.Time) float64 {
+	mastery := p.RecentScore * 100
+	elapsed := now.Sub(p.LastPracticedAt)
+	if p.LastPracticedAt.IsZero() || elapsed <= 0 {
+		return mastery
+	}
+	return mastery * math.Pow(0.5, float64(elapsed)/float64(MasteryHalfLife))
+}
+
+// MasteredUntil 返回不再練習時熟練度衰減至門檻以下的時間，上次練習時未熟練則第二個返回值為 false
+func (p CharacterProgress) MasteredUntil() (time.Time, bool) {
+	mastery := p.RecentScore * 100
+	if p.LastPracticedAt.IsZero() || mastery < MasteryThreshold {
+		return time.Time{}, false
+	}
+	halfLives := math.Log2(mastery / MasteryThreshold)
+	return p.LastPracticedAt.Add(time.Duration(halfLives * float64(MasteryHalfLife))), true
+}
@@ -0,0 +1,125 @@
+// backend/models/mastery_test.go
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestApplyScore(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	type attempt struct {
+		stroke int
+		score  float64
+		at     time.Time
+	}
+	tests := []struct {
+		name          string
+		attempts      []attempt
+		recent, avg   float64
+		mastery       float64
+		lastStroke    int
+		lastPracticed time.Time
+		masteredAt    *time.Time
+		wantAttempts  int
+	}{
+		{
+			name:          "first attempt",
+			attempts:      []attempt{{0, 0.6, at}},
+			recent:        0.6,
+			avg:           0.6,
+			mastery:       60,
+			lastPracticed: at,
+			wantAttempts:  1,
+		},
+		{
+			name:          "later attempt weighted 0.3",
+			attempts:      []attempt{{0, 0.5, at}, {1, 1.0, at.Add(time.Minute)}},
+			recent:        0.3*1.0 + 0.7*0.5,
+			avg:           0.75,
+			mastery:       65,
+			lastStroke:    1,
+			lastPracticed: at.Add(time.Minute),
+			wantAttempts:  2,
+		},
+		{
+			name:          "stale attempt only counts towards the average",
+			attempts:      []attempt{{1, 0.8, at}, {2, 0.2, at.Add(-time.Hour)}},
+			recent:        0.8,
+			avg:           0.5,
+			mastery:       80,
+			lastStroke:    2,
+			lastPracticed: at,
+			masteredAt:    &at,
+			wantAttempts:  2,
+		},
+		{
+			name:          "same time is not stale",
+			attempts:      []attempt{{0, 1.0, at}, {0, 0.0, at}},
+			recent:        0.7,
+			avg:           0.5,
+			mastery:       70,
+			lastPracticed: at,
+			masteredAt:    &at,
+			wantAttempts:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p CharacterProgress
+			for _, a := range tt.attempts {
+				p.ApplyScore(a.stroke, a.score, a.at)
+			}
+			if p.Attempts != tt.wantAttempts || p.LastStroke != tt.lastStroke {
+				t.Errorf("attempts/lastStroke = %d/%d, want %d/%d", p.Attempts, p.LastStroke, tt.wantAttempts, tt.lastStroke)
+			}
+			if !approxEqual(p.RecentScore, tt.recent) || !approxEqual(p.AvgScore, tt.avg) || !approxEqual(p.Mastery, tt.mastery) {
+				t.Errorf("recent/avg/mastery = %v/%v/%v, want %v/%v/%v", p.RecentScore, p.AvgScore, p.Mastery, tt.recent, tt.avg, tt.mastery)
+			}
+			if !p.LastPracticedAt.Equal(tt.lastPracticed) {
+				t.Errorf("lastPracticedAt = %v, want %v", p.LastPracticedAt, tt.lastPracticed)
+			}
+			switch {
+			case tt.masteredAt == nil && p.MasteredAt != nil:
+				t.Errorf("masteredAt = %v, want unset", *p.MasteredAt)
+			case tt.masteredAt != nil && (p.MasteredAt == nil || !p.MasteredAt.Equal(*tt.masteredAt)):
+				t.Errorf("masteredAt = %v, want %v", p.MasteredAt, *tt.masteredAt)
+			}
+		})
+	}
+}
+
+func TestMasteryAt(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	p := CharacterProgress{RecentScore: 1.0, LastPracticedAt: at}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want float64
+	}{
+		{"at practice time", at, 100},
+		{"before practice time", at.Add(-time.Hour), 100},
+		{"one half-life", at.Add(30 * 24 * time.Hour), 50},
+		{"two half-lives", at.Add(60 * 24 * time.Hour), 25},
+		{"half a half-life", at.Add(15 * 24 * time.Hour), 100 / math.Sqrt2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.MasteryAt(tt.now); !approxEqual(got, tt.want) {
+				t.Errorf("MasteryAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 從未練習時不衰減
+	if got := (CharacterProgress{RecentScore: 0.4}).MasteryAt(at); !approxEqual(got, 40) {
+		t.Errorf("MasteryAt without practice = %v, want 40", got)
+	}
+}
@@ -9,9 +9,24 @@ type Node struct {
 	Y float64 `json:"y"`
 }
 
+// StrokeType 基本筆畫類型
+type StrokeType string
+
+const (
+	StrokeHeng StrokeType = "橫"
+	StrokeShu  StrokeType = "豎"
+	StrokePie  StrokeType = "撇"
+	StrokeNa   StrokeType = "捺"
+	StrokeDian StrokeType = "點"
+	StrokeZhe  StrokeType = "折"
+	StrokeGou  StrokeType = "鉤"
+	StrokeTi   StrokeType = "提"
+)
+
 // Stroke 代表一個完整的筆畫，由多個節點組成
 type Stroke struct {
-	Nodes []Node `json:"nodes"`
+	Nodes []Node     `json:"nodes"`
+	Type  StrokeType `json:"type,omitempty"`
 }
 
 // CharacterPreview 用於字元選擇列表
@@ -19,6 +34,7 @@ type CharacterPreview struct {
 	ID      int    `json:"id"`
 	Name    string `json:"name"`
 	Preview string `json:"preview"`
+	OrgID   int    `json:"orgId,omitempty"` // 0 表示所有組織共用的內建字元
 }
 
 // Character 代表完整的字元資料
@@ -27,20 +43,102 @@ type Character struct {
 	Name       string   `json:"name"`
 	SVGUrl     string   `json:"svgUrl"`
 	StrokeData []Stroke `json:"strokeData"`
+	OrgID      int      `json:"orgId,omitempty"` // 0 表示所有組織共用的內建字元
+}
+
+// Deck 字卡組，字元依建議的學習順序排列
+type Deck struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	CharacterIDs []int  `json:"characterIds"`
+	OrgID        int    `json:"orgId,omitempty"` // 0 表示所有組織共用的內建字卡組
+}
+
+// CharacterRequest 創建或更新自訂字元請求
+type CharacterRequest struct {
+	Name       string   `json:"name"`
+	SVGUrl     string   `json:"svgUrl"`
+	StrokeData []Stroke `json:"strokeData"`
 }
 
+// DeckRequest 創建自訂字卡組請求
+type DeckRequest struct {
+	Name         string `json:"name"`
+	CharacterIDs []int  `json:"characterIds"`
+}
+
+// DefaultOrganizationID 預設組織，未指定組織的登入與舊的 Token 歸屬於此
+const DefaultOrganizationID = 1
+
+// Organization 組織（學校），用戶、班級與自訂字元都屬於某一組織
+type Organization struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateOrganizationRequest 創建組織請求，同時建立組織的管理員帳號
+type CreateOrganizationRequest struct {
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+	AdminUsername string `json:"adminUsername"`
+	AdminPassword string `json:"adminPassword"`
+}
+
+// 用戶角色
+const (
+	RoleStudent = "student"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
 // User 使用者資料
 type User struct {
 	ID       int    `json:"id"`
 	Username string `json:"username"`
 	Password string `json:"-"` // 不在 JSON 中返回密碼
 	Email    string `json:"email,omitempty"`
+	Role     string `json:"role"`
+	OrgID    int    `json:"orgId"`
+
+	LeaderboardOptOut bool   `json:"leaderboardOptOut"` // 不出現在排行榜
+	Locale            string `json:"locale,omitempty"`  // 偏好語系，空字串表示依 Accept-Language
+
+	DeletionRequestedAt *time.Time `json:"deletionRequestedAt,omitempty"` // 申請刪除帳號的時間，寬限期後清除資料
+	DeletedAt           *time.Time `json:"deletedAt,omitempty"`           // 資料已清除並匿名化的時間
+}
+
+// PreferenceSettings 用戶偏好設定
+type PreferenceSettings struct {
+	Locale string `json:"locale"` // zh-TW、zh-CN 或 en，空字串表示依 Accept-Language
+}
+
+// PrivacySettings 用戶隱私設定
+type PrivacySettings struct {
+	LeaderboardOptOut bool `json:"leaderboardOptOut"`
 }
 
 // LoginRequest 登入請求
 type LoginRequest struct {
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	Organization string `json:"organization"` // 組織代稱，預設為預設組織
+}
+
+// RegisterRequest 註冊請求
+type RegisterRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Email    string `json:"email"`
+	Role     string `json:"role"` // 只能為 student，老師由組織管理員指派
+
+	Organization string `json:"organization"` // 組織代稱，預設為預設組織
+}
+
+// RoleUpdateRequest 管理員變更用戶角色請求
+type RoleUpdateRequest struct {
+	Role string `json:"role"` // student 或 teacher
 }
 
 // LoginResponse 登入回應
@@ -66,23 +164,419 @@ type StrokeRecord struct {
 	StrokeIndex int       `json:"strokeIndex"`
 	Path        []Node    `json:"path"`
 	Score       float64   `json:"score"`
+	ClientID    string    `json:"clientId,omitempty"` // 離線同步時由客戶端產生的冪等鍵
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+// StrokeRecordQuery 筆畫記錄查詢條件，未設定的篩選條件不限制
+type StrokeRecordQuery struct {
+	UserID      int
+	CharacterID *int
+	StrokeIndex *int
+	From        time.Time // 包含
+	To          time.Time // 不包含
+	MinScore    *float64
+	MaxScore    *float64
+	Cursor      string // 上一頁返回的 NextCursor
+	Limit       int
+}
+
+// StrokeRecordPage 一頁筆畫記錄
+type StrokeRecordPage struct {
+	Records    []StrokeRecord `json:"records"`
+	NextCursor string         `json:"nextCursor,omitempty"` // 空字串表示沒有下一頁
+}
+
 // StrokeRecordResponse 筆畫記錄回應
 type StrokeRecordResponse struct {
-	RecordID        int    `json:"recordId"`
-	SimplifiedNodes []Node `json:"simplifiedNodes"`
+	RecordID             int               `json:"recordId"`
+	SimplifiedNodes      []Node            `json:"simplifiedNodes"`
+	UnlockedAchievements []UserAchievement `json:"unlockedAchievements,omitempty"`
+}
+
+// BatchStrokeItem 離線同步的單筆筆畫記錄
+type BatchStrokeItem struct {
+	ClientID        string    `json:"clientId"`
+	ClientTimestamp time.Time `json:"clientTimestamp"`
+	CharacterID     int       `json:"characterId"`
+	StrokeIndex     int       `json:"strokeIndex"`
+	Path            []Node    `json:"path"`
+	Score           float64   `json:"score"`
+}
+
+// BatchStrokeRequest 離線批次同步請求
+type BatchStrokeRequest struct {
+	UserID  int               `json:"userId"`
+	Records []BatchStrokeItem `json:"records"`
+}
+
+// 批次同步單筆結果狀態
+const (
+	BatchItemCreated   = "created"
+	BatchItemDuplicate = "duplicate"
+	BatchItemInvalid   = "invalid"
+)
+
+// BatchStrokeResult 批次同步的單筆結果
+type BatchStrokeResult struct {
+	ClientID string `json:"clientId"`
+	Status   string `json:"status"`
+	RecordID int    `json:"recordId,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// BatchStrokeResponse 批次同步回應，結果順序與請求一致
+type BatchStrokeResponse struct {
+	Results              []BatchStrokeResult `json:"results"`
+	UnlockedAchievements []UserAchievement   `json:"unlockedAchievements,omitempty"`
 }
 
 // CharacterProgress 字元進度
 type CharacterProgress struct {
+	CharacterID     int        `json:"characterId"`
+	Attempts        int        `json:"attempts"`
+	AvgScore        float64    `json:"avgScore"`
+	RecentScore     float64    `json:"recentScore"` // 最近得分的指數移動平均
+	Mastery         float64    `json:"mastery"`
+	LastStroke      int        `json:"lastStroke"`
+	LastPracticedAt time.Time  `json:"lastPracticedAt"`
+	MasteredAt      *time.Time `json:"masteredAt,omitempty"` // 首次達到熟練的時間
+}
+
+// UserProgress 用戶進度映射 - 字元ID對應進度
+type UserProgress map[int]CharacterProgress
+
+// HistoryInterval 進度歷史的彙總區間
+type HistoryInterval string
+
+const (
+	HistoryIntervalDay  HistoryInterval = "day"
+	HistoryIntervalWeek HistoryInterval = "week"
+)
+
+// ProgressHistoryQuery 進度歷史查詢條件
+type ProgressHistoryQuery struct {
+	UserID   int
+	From     time.Time // 以 From 的時區劃分區間
+	To       time.Time // 不包含
+	Interval HistoryInterval
+}
+
+// ProgressHistoryPoint 進度歷史中單一區間的彙總
+type ProgressHistoryPoint struct {
+	PeriodStart        time.Time `json:"periodStart"`
+	Attempts           int       `json:"attempts"`
+	AvgScore           float64   `json:"avgScore"`
+	MasteredCharacters int       `json:"masteredCharacters"` // 區間結束時已熟練的字元數
+}
+
+// DailyGoal 每日練習目標，未設定的目標以 0 表示
+type DailyGoal struct {
+	UserID           int    `json:"userId"`
+	TargetCharacters int    `json:"targetCharacters"`
+	TargetMinutes    int    `json:"targetMinutes"`
+	Timezone         string `json:"timezone"` // IANA 時區，用於判斷「今天」
+}
+
+// DailyActivity 用戶某一天（用戶時區）的練習統計
+type DailyActivity struct {
+	Date            string    `json:"date"`
+	Strokes         int       `json:"strokes"`
+	CharacterIDs    []int     `json:"characterIds"`
+	PracticeSeconds int       `json:"practiceSeconds"`
+	LastPracticeAt  time.Time `json:"lastPracticeAt"`
+}
+
+// DailyGoalStatus 今日目標完成狀態
+type DailyGoalStatus struct {
+	Goal                DailyGoal `json:"goal"`
+	Date                string    `json:"date"`
+	CharactersPracticed int       `json:"charactersPracticed"`
+	MinutesPracticed    int       `json:"minutesPracticed"`
+	Completed           bool      `json:"completed"`
+}
+
+// Streak 連續練習天數
+type Streak struct {
+	UserID           int    `json:"userId"`
+	Current          int    `json:"current"`
+	Longest          int    `json:"longest"`
+	LastPracticeDate string `json:"lastPracticeDate,omitempty"`
+}
+
+// Achievement 成就定義
+type Achievement struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// UserAchievement 用戶已解鎖的成就
+type UserAchievement struct {
+	UserID        int       `json:"userId"`
+	AchievementID string    `json:"achievementId"`
+	UnlockedAt    time.Time `json:"unlockedAt"`
+}
+
+// AchievementStatus 成就及用戶的解鎖狀態
+type AchievementStatus struct {
+	Achievement
+	Unlocked   bool       `json:"unlocked"`
+	UnlockedAt *time.Time `json:"unlockedAt,omitempty"`
+}
+
+// LeaderboardPeriod 排行榜統計期間
+type LeaderboardPeriod string
+
+const (
+	LeaderboardWeekly  LeaderboardPeriod = "week"
+	LeaderboardMonthly LeaderboardPeriod = "month"
+	LeaderboardAllTime LeaderboardPeriod = "all"
+)
+
+// LeaderboardMetric 排行榜排名依據
+type LeaderboardMetric string
+
+const (
+	MetricMastered LeaderboardMetric = "mastered" // 期間內熟練的字元數
+	MetricAccuracy LeaderboardMetric = "accuracy" // 期間內平均得分
+	MetricVolume   LeaderboardMetric = "volume"   // 期間內練習筆畫數
+)
+
+// PracticeAggregate 用戶在某一期間的練習彙總
+type PracticeAggregate struct {
+	Strokes            int     `json:"strokes"`
+	ScoreSum           float64 `json:"scoreSum"`
+	MasteredCharacters int     `json:"masteredCharacters"`
+}
+
+// LeaderboardQuery 排行榜查詢條件
+type LeaderboardQuery struct {
+	OrgID   int
+	Period  LeaderboardPeriod
+	Metric  LeaderboardMetric
+	UserIDs []int // 限定範圍內的用戶，nil 表示全域
+	Limit   int
+	At      time.Time // 決定目前所在的週或月
+}
+
+// LeaderboardEntry 排行榜項目
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"`
+	UserID   int     `json:"userId"`
+	Username string  `json:"username"`
+	Value    float64 `json:"value"`
+}
+
+// RecommendationReason 推薦原因
+type RecommendationReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Recommendation 推薦練習的字元
+type Recommendation struct {
+	CharacterID int                    `json:"characterId"`
+	Name        string                 `json:"name"`
+	Score       float64                `json:"score"` // 越高越優先
+	Reasons     []RecommendationReason `json:"reasons"`
+}
+
+// StrokeTypeWeakness 用戶某一種筆畫類型的得分彙總
+type StrokeTypeWeakness struct {
+	Type              StrokeType `json:"type"`
+	Attempts          int        `json:"attempts"`
+	AvgScore          float64    `json:"avgScore"`
+	Weak              bool       `json:"weak"`
+	Message           string     `json:"message"`
+	DrillCharacterIDs []int      `json:"drillCharacterIds"` // 建議加強練習的字元
+}
+
+// Classroom 班級
+type Classroom struct {
+	ID            int       `json:"id"`
+	OrgID         int       `json:"orgId"`
+	Name          string    `json:"name"`
+	TeacherID     int       `json:"teacherId"`
+	EnrolmentCode string    `json:"enrolmentCode,omitempty"` // 只提供給老師
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// ClassMembership 學生加入班級的記錄
+type ClassMembership struct {
+	ClassID  int       `json:"classId"`
+	UserID   int       `json:"userId"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// CreateClassroomRequest 創建班級請求
+type CreateClassroomRequest struct {
+	Name string `json:"name"`
+}
+
+// JoinClassroomRequest 加入班級請求
+type JoinClassroomRequest struct {
+	EnrolmentCode string `json:"enrolmentCode"`
+}
+
+// RosterEntry 班級名單項目
+type RosterEntry struct {
+	UserID   int       `json:"userId"`
+	Username string    `json:"username"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// 作業完成狀態
+const (
+	AssignmentNotStarted = "not_started"
+	AssignmentInProgress = "in_progress"
+	AssignmentCompleted  = "completed"
+	AssignmentOverdue    = "overdue"
+)
+
+// Assignment 班級作業：在期限前將指定字元練習到要求的熟練度
+type Assignment struct {
+	ID               int       `json:"id"`
+	ClassID          int       `json:"classId"`
+	Title            string    `json:"title"`
+	CharacterIDs     []int     `json:"characterIds"`
+	DueAt            time.Time `json:"dueAt"`
+	MasteryThreshold float64   `json:"masteryThreshold"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// AssignmentRequest 創建或更新作業請求
+type AssignmentRequest struct {
+	Title            string    `json:"title"`
+	CharacterIDs     []int     `json:"characterIds"`
+	DueAt            time.Time `json:"dueAt"`
+	MasteryThreshold float64   `json:"masteryThreshold"` // 0 表示使用預設熟練門檻
+}
+
+// AssignmentCharacterStatus 作業中單一字元的完成狀態
+type AssignmentCharacterStatus struct {
 	CharacterID int     `json:"characterId"`
 	Attempts    int     `json:"attempts"`
-	AvgScore    float64 `json:"avgScore"`
 	Mastery     float64 `json:"mastery"`
-	LastStroke  int     `json:"lastStroke"`
+	Completed   bool    `json:"completed"`
 }
 
-// UserProgress 用戶進度映射 - 字元ID對應進度
-type UserProgress map[int]CharacterProgress
+// AssignmentStudentStatus 學生的作業完成狀態
+type AssignmentStudentStatus struct {
+	UserID              int                         `json:"userId"`
+	Username            string                      `json:"username,omitempty"`
+	Status              string                      `json:"status"`
+	CompletedCharacters int                         `json:"completedCharacters"`
+	TotalCharacters     int                         `json:"totalCharacters"`
+	Characters          []AssignmentCharacterStatus `json:"characters"`
+}
+
+// StudentAssignment 學生的作業及其完成狀態
+type StudentAssignment struct {
+	Assignment
+	Progress AssignmentStudentStatus `json:"progress"`
+}
+
+// StrokeStat 用戶某一筆畫的練習彙總
+type StrokeStat struct {
+	UserID          int       `json:"userId"`
+	CharacterID     int       `json:"characterId"`
+	StrokeIndex     int       `json:"strokeIndex"`
+	Attempts        int       `json:"attempts"`
+	ScoreSum        float64   `json:"scoreSum"`
+	BestScore       float64   `json:"bestScore"`
+	Failures        int       `json:"failures"` // 得分低於 WeakStrokeScore 的次數
+	LastPracticedAt time.Time `json:"lastPracticedAt"`
+}
+
+// CharacterClassStats 班級在單一字元上的表現
+type CharacterClassStats struct {
+	CharacterID int     `json:"characterId"`
+	Name        string  `json:"name"`
+	Students    int     `json:"students"`
+	Attempts    int     `json:"attempts"`
+	AvgScore    float64 `json:"avgScore"`
+}
+
+// FailedStrokeStats 班級最常失敗的筆畫
+type FailedStrokeStats struct {
+	CharacterID int     `json:"characterId"`
+	StrokeIndex int     `json:"strokeIndex"`
+	Attempts    int     `json:"attempts"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failureRate"`
+}
+
+// InactiveStudent 近期沒有練習的學生
+type InactiveStudent struct {
+	UserID          int        `json:"userId"`
+	Username        string     `json:"username"`
+	LastPracticedAt *time.Time `json:"lastPracticedAt,omitempty"`
+}
+
+// AssignmentCompletion 作業的班級完成率
+type AssignmentCompletion struct {
+	AssignmentID   int       `json:"assignmentId"`
+	Title          string    `json:"title"`
+	DueAt          time.Time `json:"dueAt"`
+	Completed      int       `json:"completed"`
+	Total          int       `json:"total"`
+	CompletionRate float64   `json:"completionRate"`
+}
+
+// ClassDashboard 班級學習分析
+type ClassDashboard struct {
+	ClassID           int                    `json:"classId"`
+	Students          int                    `json:"students"`
+	Characters        []CharacterClassStats  `json:"characters"`
+	MostFailedStrokes []FailedStrokeStats    `json:"mostFailedStrokes"`
+	InactiveStudents  []InactiveStudent      `json:"inactiveStudents"`
+	Assignments       []AssignmentCompletion `json:"assignments"`
+}
+
+// 監護人連結狀態
+const (
+	GuardianLinkPending  = "pending"
+	GuardianLinkApproved = "approved"
+	GuardianLinkRejected = "rejected"
+)
+
+// GuardianLink 監護人與孩子帳號的連結，經孩子帳號同意後監護人可唯讀查看學習資料
+type GuardianLink struct {
+	ID          int        `json:"id"`
+	GuardianID  int        `json:"guardianId"`
+	ChildID     int        `json:"childId"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+}
+
+// GuardianInvitationRequest 監護人邀請請求
+type GuardianInvitationRequest struct {
+	ChildUsername string `json:"childUsername"`
+}
+
+// 稽核記錄動作
+const (
+	AuditAccountDeletionRequested = "account_deletion_requested"
+	AuditAccountDeletionCancelled = "account_deletion_cancelled"
+	AuditAccountPurged            = "account_purged"
+	AuditRoleChanged              = "role_changed"
+)
+
+// AuditEntry 稽核記錄，ActorID 為 0 表示由系統排程執行
+type AuditEntry struct {
+	ID           int       `json:"id"`
+	OrgID        int       `json:"orgId"`
+	ActorID      int       `json:"actorId"`
+	Action       string    `json:"action"`
+	TargetUserID int       `json:"targetUserId"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// AccountDeletionStatus 帳號刪除申請狀態
+type AccountDeletionStatus struct {
+	UserID      int       `json:"userId"`
+	RequestedAt time.Time `json:"requestedAt"`
+	PurgeAt     time.Time `json:"purgeAt"`
+}
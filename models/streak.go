@@ -0,0 +1,126 @@
+// backend/models/streak.go
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// DateLayout 日期字串格式
+	DateLayout = "2006-01-02"
+	// DefaultDailyCharacters 未設定目標時每日應練習的字元數
+	DefaultDailyCharacters = 5
+	// PracticeSessionGap 相鄰兩筆記錄間隔超過此值即不計入練習時間
+	PracticeSessionGap = 5 * time.Minute
+)
+
+// DefaultDailyGoal 返回用戶的預設每日目標
+func DefaultDailyGoal(userID int) DailyGoal {
+	return DailyGoal{
+		UserID:           userID,
+		TargetCharacters: DefaultDailyCharacters,
+		Timezone:         "UTC",
+	}
+}
+
+// Location 返回目標設定的時區，無效時使用 UTC
+func (g DailyGoal) Location() *time.Location {
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDate 返回時間在用戶時區的日期
+func (g DailyGoal) LocalDate(t time.Time) string {
+	return t.In(g.Location()).Format(DateLayout)
+}
+
+// Status 依當日練習統計計算目標完成狀態，所有已設定的目標都達成才算完成
+func (g DailyGoal) Status(activity DailyActivity) DailyGoalStatus {
+	status := DailyGoalStatus{
+		Goal:                g,
+		Date:                activity.Date,
+		CharactersPracticed: len(activity.CharacterIDs),
+		MinutesPracticed:    activity.PracticeSeconds / 60,
+	}
+	status.Completed = (g.TargetCharacters > 0 || g.TargetMinutes > 0) &&
+		status.CharactersPracticed >= g.TargetCharacters &&
+		status.MinutesPracticed >= g.TargetMinutes
+	return status
+}
+
+// AddPractice 將一次筆畫練習併入當日統計
+func (a *DailyActivity) AddPractice(characterID int, at time.Time) {
+	a.Strokes++
+
+	practiced := false
+	for _, id := range a.CharacterIDs {
+		if id == characterID {
+			practiced = true
+			break
+		}
+	}
+	if !practiced {
+		a.CharacterIDs = append(a.CharacterIDs, characterID)
+	}
+
+	// 以相鄰記錄的間隔估算練習時間
+	if !a.LastPracticeAt.IsZero() {
+		gap := at.Sub(a.LastPracticeAt)
+		if gap > 0 && gap <= PracticeSessionGap {
+			a.PracticeSeconds += int(gap.Seconds())
+		}
+	}
+	if at.After(a.LastPracticeAt) {
+		a.LastPracticeAt = at
+	}
+}
+
+// RecordPractice 以用戶當地日期更新連續天數
+func (s *Streak) RecordPractice(date string) {
+	if s.LastPracticeDate != "" && date <= s.LastPracticeDate {
+		return
+	}
+
+	if s.LastPracticeDate != "" && nextDate(s.LastPracticeDate) == date {
+		s.Current++
+	} else {
+		s.Current = 1
+	}
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	s.LastPracticeDate = date
+}
+
+// StreakFromDates 依所有練習日期重新計算連續天數，用於補登較早日期的練習
+func StreakFromDates(userID int, dates []string) Streak {
+	sorted := append([]string(nil), dates...)
+	sort.Strings(sorted)
+
+	streak := Streak{UserID: userID}
+	for _, date := range sorted {
+		streak.RecordPractice(date)
+	}
+	return streak
+}
+
+// CurrentOn 返回指定日期的連續天數，今天或昨天沒有練習則連續中斷
+func (s Streak) CurrentOn(today string) int {
+	if s.LastPracticeDate == today || nextDate(s.LastPracticeDate) == today {
+		return s.Current
+	}
+	return 0
+}
+
+// nextDate 返回日期字串的下一天
+func nextDate(date string) string {
+	day, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return ""
+	}
+	return day.AddDate(0, 0, 1).Format(DateLayout)
+}
@@ -0,0 +1,63 @@
+// backend/models/streak_test.go
+package models
+
+import "testing"
+
+func TestRecordPractice(t *testing.T) {
+	tests := []struct {
+		name             string
+		dates            []string
+		current, longest int
+		last             string
+	}{
+		{"first day", []string{"2026-03-01"}, 1, 1, "2026-03-01"},
+		{"same day twice", []string{"2026-03-01", "2026-03-01"}, 1, 1, "2026-03-01"},
+		{"consecutive days", []string{"2026-03-01", "2026-03-02", "2026-03-03"}, 3, 3, "2026-03-03"},
+		{"across month end", []string{"2026-02-28", "2026-03-01"}, 2, 2, "2026-03-01"},
+		{"gap resets current", []string{"2026-03-01", "2026-03-02", "2026-03-04"}, 1, 2, "2026-03-04"},
+		{"earlier date ignored", []string{"2026-03-02", "2026-03-01"}, 1, 1, "2026-03-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Streak
+			for _, date := range tt.dates {
+				s.RecordPractice(date)
+			}
+			if s.Current != tt.current || s.Longest != tt.longest || s.LastPracticeDate != tt.last {
+				t.Errorf("streak = %d/%d last %s, want %d/%d last %s", s.Current, s.Longest, s.LastPracticeDate, tt.current, tt.longest, tt.last)
+			}
+		})
+	}
+}
+
+func TestStreakFromDates(t *testing.T) {
+	tests := []struct {
+		name             string
+		dates            []string
+		current, longest int
+		last             string
+	}{
+		{"no practice", nil, 0, 0, ""},
+		{"out of order", []string{"2026-03-03", "2026-03-01", "2026-03-02"}, 3, 3, "2026-03-03"},
+		{"backfilled day joins streak", []string{"2026-03-01", "2026-03-03", "2026-03-02"}, 3, 3, "2026-03-03"},
+		{"duplicates", []string{"2026-03-02", "2026-03-01", "2026-03-02"}, 2, 2, "2026-03-02"},
+		{"gap keeps longest", []string{"2026-03-10", "2026-03-01", "2026-03-02", "2026-03-03"}, 1, 3, "2026-03-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := StreakFromDates(7, tt.dates)
+			if s.UserID != 7 || s.Current != tt.current || s.Longest != tt.longest || s.LastPracticeDate != tt.last {
+				t.Errorf("streak = %+v, want user 7 with %d/%d last %q", s, tt.current, tt.longest, tt.last)
+			}
+		})
+	}
+}
+
+func TestStreakCurrentOn(t *testing.T) {
+	s := StreakFromDates(1, []string{"2026-03-01", "2026-03-02"})
+	for today, want := range map[string]int{"2026-03-02": 2, "2026-03-03": 2, "2026-03-04": 0} {
+		if got := s.CurrentOn(today); got != want {
+			t.Errorf("CurrentOn(%s) = %d, want %d", today, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// backend/models/stroke_type.go
+package models
+
+import "math"
+
+const (
+	// dotLength 筆畫總長度低於此值視為點
+	dotLength = 60.0
+	// turnAngle 相鄰線段轉向超過此角度（度）視為轉折
+	turnAngle = 60.0
+	// hookRatio 轉折後的最後一段短於總長度此比例時視為鉤
+	hookRatio = 0.3
+	// axisRatio 次軸位移與主軸位移之比低於此值視為水平或垂直
+	axisRatio = 0.35
+)
+
+// StrokeTypes 所有基本筆畫類型
+var StrokeTypes = []StrokeType{StrokeHeng, StrokeShu, StrokePie, StrokeNa, StrokeDian, StrokeZhe, StrokeGou, StrokeTi}
+
+// ClassifyStroke 依筆畫節點的幾何形狀判斷基本筆畫類型（座標 Y 軸向下）
+func ClassifyStroke(nodes []Node) StrokeType {
+	if len(nodes) < 2 {
+		return StrokeDian
+	}
+
+	total := 0.0
+	for i := 1; i < len(nodes); i++ {
+		total += distance(nodes[i-1], nodes[i])
+	}
+	if total < dotLength {
+		return StrokeDian
+	}
+
+	// 找出轉向最大的節點
+	maxTurn, turnIndex := 0.0, 0
+	for i := 1; i < len(nodes)-1; i++ {
+		turn := turnBetween(nodes[i-1], nodes[i], nodes[i+1])
+		if turn > maxTurn {
+			maxTurn, turnIndex = turn, i
+		}
+	}
+	if maxTurn >= turnAngle {
+		tail := 0.0
+		for i := turnIndex + 1; i < len(nodes); i++ {
+			tail += distance(nodes[i-1], nodes[i])
+		}
+		if tail < total*hookRatio {
+			return StrokeGou
+		}
+		return StrokeZhe
+	}
+
+	dx := nodes[len(nodes)-1].X - nodes[0].X
+	dy := nodes[len(nodes)-1].Y - nodes[0].Y
+	switch {
+	case math.Abs(dy) <= math.Abs(dx)*axisRatio:
+		return StrokeHeng
+	case math.Abs(dx) <= math.Abs(dy)*axisRatio:
+		return StrokeShu
+	case dy < 0 && dx > 0:
+		return StrokeTi
+	case dx < 0:
+		return StrokePie
+	}
+	return StrokeNa
+}
+
+// distance 計算兩點距離
+func distance(a, b Node) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// turnBetween 計算經過中間點時方向改變的角度（度）
+func turnBetween(a, b, c Node) float64 {
+	in := math.Atan2(b.Y-a.Y, b.X-a.X)
+	out := math.Atan2(c.Y-b.Y, c.X-b.X)
+	turn := math.Abs(out-in) * 180 / math.Pi
+	if turn > 180 {
+		turn = 360 - turn
+	}
+	return turn
+}
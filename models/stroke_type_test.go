@@ -0,0 +1,29 @@
+// backend/models/stroke_type_test.go
+package models
+
+import "testing"
+
+func TestClassifyStroke(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []Node
+		want  StrokeType
+	}{
+		{"horizontal", []Node{{X: 10, Y: 50}, {X: 90, Y: 52}}, StrokeHeng},
+		{"vertical", []Node{{X: 50, Y: 10}, {X: 50, Y: 90}}, StrokeShu},
+		{"left-falling", []Node{{X: 70, Y: 10}, {X: 20, Y: 80}}, StrokePie},
+		{"right-falling", []Node{{X: 20, Y: 10}, {X: 80, Y: 80}}, StrokeNa},
+		{"short dot", []Node{{X: 40, Y: 40}, {X: 50, Y: 55}}, StrokeDian},
+		{"single node", []Node{{X: 40, Y: 40}}, StrokeDian},
+		{"turning", []Node{{X: 10, Y: 20}, {X: 80, Y: 20}, {X: 80, Y: 90}}, StrokeZhe},
+		{"hook", []Node{{X: 50, Y: 10}, {X: 50, Y: 90}, {X: 40, Y: 80}}, StrokeGou},
+		{"rising", []Node{{X: 20, Y: 80}, {X: 80, Y: 50}}, StrokeTi},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyStroke(tt.nodes); got != tt.want {
+				t.Errorf("ClassifyStroke(%v) = %s, want %s", tt.nodes, got, tt.want)
+			}
+		})
+	}
+}
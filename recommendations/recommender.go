@@ -0,0 +1,153 @@
+// backend/recommendations/recommender.go
+package recommendations
+
+import (
+	"backend/i18n"
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// 推薦原因代碼
+const (
+	ReasonDueReview  = "due_review"
+	ReasonWeakStroke = "weak_stroke"
+	ReasonInProgress = "in_progress"
+	ReasonNextInDeck = "next_in_deck"
+)
+
+// ReviewInterval 未熟練的字元超過此時間未練習即需要複習
+const ReviewInterval = 3 * 24 * time.Hour
+
+// 各推薦原因的基礎權重
+const (
+	dueReviewWeight  = 3.0
+	weakStrokeWeight = 2.0
+	inProgressWeight = 1.5
+	nextInDeckWeight = 1.0
+)
+
+// Recommender 綜合進度、弱項筆畫、待複習字元與字卡組順序推薦下一個練習的字元
+type Recommender struct {
+	store storage.Storage
+}
+
+// NewRecommender 創建一個新的推薦器
+func NewRecommender(store storage.Storage) *Recommender {
+	return &Recommender{
+		store: store,
+	}
+}
+
+// Recommend 返回依優先順序排列的推薦字元
+func (r *Recommender) Recommend(ctx context.Context, orgID, userID int, limit int) ([]models.Recommendation, error) {
+	now := time.Now()
+	progress, err := r.store.GetUserProgress(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := r.store.GetStrokeStats(ctx, orgID, []int{userID})
+	if err != nil {
+		return nil, err
+	}
+	decks, err := r.store.GetDecks(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	characters, err := r.store.GetCharacters(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make(map[int]*models.Recommendation)
+	add := func(characterID int, score float64, code, message string) {
+		candidate, exists := candidates[characterID]
+		if !exists {
+			candidate = &models.Recommendation{CharacterID: characterID}
+			candidates[characterID] = candidate
+		}
+		candidate.Score += score
+		candidate.Reasons = append(candidate.Reasons, models.RecommendationReason{Code: code, Message: message})
+	}
+
+	// 待複習與練習中的字元
+	for characterID, charProgress := range progress {
+		mastered := charProgress.Mastery >= models.MasteryThreshold
+		switch {
+		case charProgress.MasteredAt != nil && !mastered:
+			add(characterID, dueReviewWeight+(models.MasteryThreshold-charProgress.Mastery)/100,
+				ReasonDueReview, i18n.T(ctx, "Mastery has faded since your last practice"))
+		case !mastered && now.Sub(charProgress.LastPracticedAt) >= ReviewInterval:
+			add(characterID, dueReviewWeight, ReasonDueReview, i18n.T(ctx, "Not practised for a while"))
+		case !mastered:
+			add(characterID, inProgressWeight+(models.MasteryThreshold-charProgress.Mastery)/100,
+				ReasonInProgress, i18n.T(ctx, "Mastery is %.0f, keep going", charProgress.Mastery))
+		}
+	}
+
+	// 弱項筆畫
+	for _, stat := range stats {
+		if stat.Attempts == 0 {
+			continue
+		}
+		avgScore := stat.ScoreSum / float64(stat.Attempts)
+		if avgScore >= models.WeakStrokeScore {
+			continue
+		}
+		add(stat.CharacterID, weakStrokeWeight+(models.WeakStrokeScore-avgScore),
+			ReasonWeakStroke, i18n.T(ctx, "Stroke %d averages %.2f", stat.StrokeIndex+1, avgScore))
+	}
+
+	// 每個字卡組中下一個尚未練習、且有筆畫資料可以練習的字元
+	practisable := make(map[int]bool)
+	for _, deck := range decks {
+		for position, characterID := range deck.CharacterIDs {
+			if _, started := progress[characterID]; started {
+				continue
+			}
+			ok, checked := practisable[characterID]
+			if !checked {
+				character, err := r.store.GetCharacterByID(ctx, orgID, characterID)
+				if err != nil && !errors.Is(err, storage.ErrNotFound) {
+					return nil, err
+				}
+				ok = character != nil && len(character.StrokeData) > 0
+				practisable[characterID] = ok
+			}
+			if !ok {
+				continue
+			}
+			if _, exists := candidates[characterID]; !exists {
+				add(characterID, nextInDeckWeight-float64(position)/100,
+					ReasonNextInDeck, i18n.T(ctx, "Next character in %s", deck.Name))
+			}
+			break
+		}
+	}
+
+	names := make(map[int]string)
+	for _, character := range characters {
+		names[character.ID] = character.Name
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.Name = names[candidate.CharacterID]
+		recommendations = append(recommendations, *candidate)
+	}
+
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].CharacterID < recommendations[j].CharacterID
+	})
+
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
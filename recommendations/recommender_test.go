@@ -0,0 +1,94 @@
+// backend/recommendations/recommender_test.go
+package recommendations
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/storage/memory"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// missingDetails 模擬部分字元沒有筆畫資料的儲存
+type missingDetails struct {
+	*memory.MemoryStorage
+	missing map[int]bool
+}
+
+func (s missingDetails) GetCharacterByID(ctx context.Context, orgID, id int) (*models.Character, error) {
+	if s.missing[id] {
+		return nil, fmt.Errorf("character %d: %w", id, storage.ErrNotFound)
+	}
+	return s.MemoryStorage.GetCharacterByID(ctx, orgID, id)
+}
+
+// reasonsFor 返回推薦中指定字元的原因代碼
+func reasonsFor(recommendations []models.Recommendation, characterID int) []string {
+	var codes []string
+	for _, recommendation := range recommendations {
+		if recommendation.CharacterID == characterID {
+			for _, reason := range recommendation.Reasons {
+				codes = append(codes, reason.Code)
+			}
+		}
+	}
+	return codes
+}
+
+func TestRecommendWeakStrokesFromStats(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStorage()
+	at := time.Now().Add(-time.Hour)
+
+	// 字元 1 第一筆平均 0.3，第二筆平均 0.9
+	for i, p := range []struct {
+		stroke int
+		score  float64
+	}{{0, 0.2}, {0, 0.4}, {1, 0.9}} {
+		record, err := store.CreateStrokeRecord(ctx, models.StrokeRecord{
+			UserID:      1,
+			CharacterID: 1,
+			StrokeIndex: p.stroke,
+			Path:        []models.Node{{X: 0, Y: 0}, {X: 10, Y: 10}},
+			Score:       p.score,
+			CreatedAt:   at.Add(time.Duration(i) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("CreateStrokeRecord: %v", err)
+		}
+		if err := storage.ApplyStrokeRecord(ctx, store, models.DefaultOrganizationID, *record); err != nil {
+			t.Fatalf("ApplyStrokeRecord: %v", err)
+		}
+	}
+
+	recommendations, err := NewRecommender(store).Recommend(ctx, models.DefaultOrganizationID, 1, 0)
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	weak := 0
+	for _, code := range reasonsFor(recommendations, 1) {
+		if code == ReasonWeakStroke {
+			weak++
+		}
+	}
+	if weak != 1 {
+		t.Fatalf("character 1 reasons = %v, want exactly one weak stroke", reasonsFor(recommendations, 1))
+	}
+}
+
+func TestRecommendSkipsDeckCharactersWithoutDetails(t *testing.T) {
+	store := missingDetails{MemoryStorage: memory.NewMemoryStorage(), missing: map[int]bool{1: true}}
+
+	recommendations, err := NewRecommender(store).Recommend(context.Background(), models.DefaultOrganizationID, 1, 0)
+	if err != nil {
+		t.Fatalf("Recommend: %v", err)
+	}
+	if codes := reasonsFor(recommendations, 1); len(codes) != 0 {
+		t.Errorf("character 1 without details recommended for %v", codes)
+	}
+	if codes := reasonsFor(recommendations, 2); len(codes) != 1 || codes[0] != ReasonNextInDeck {
+		t.Errorf("character 2 reasons = %v, want next in deck", codes)
+	}
+}
@@ -2,27 +2,58 @@
 package routes
 
 import (
+	"backend/achievements"
+	"backend/apierror"
 	"backend/configs"
+	"backend/export"
 	"backend/handlers"
+	"backend/i18n"
 	"backend/middleware"
-	"backend/storage/memory"
+	"backend/recommendations"
+	"backend/storage"
+	"net/http"
 
 	"github.com/gorilla/mux"
 )
 
-// SetupRoutes 設置 API 路由
-func SetupRoutes(config *configs.Config) *mux.Router {
-	// 初始化儲存
-	store := memory.NewMemoryStorage()
+// SetupRoutes 以指定的儲存與訊息目錄設置 API 路由
+func SetupRoutes(config *configs.Config, store storage.Storage, locales *i18n.Bundle) *mux.Router {
+	// 初始化成就引擎、推薦器與匯出器
+	achievementEngine := achievements.NewEngine(store, achievements.DefaultRules)
+	recommender := recommendations.NewRecommender(store)
+	exporter := export.NewExporter(store, achievementEngine)
 
 	// 初始化處理程序
 	authHandler := handlers.NewAuthHandler(store, config)
 	characterHandler := handlers.NewCharacterHandler(store)
-	strokeHandler := handlers.NewStrokeHandler(store)
+	strokeHandler := handlers.NewStrokeHandler(store, achievementEngine)
 	progressHandler := handlers.NewProgressHandler(store)
+	goalHandler := handlers.NewGoalHandler(store)
+	achievementHandler := handlers.NewAchievementHandler(store, achievementEngine)
+	leaderboardHandler := handlers.NewLeaderboardHandler(store)
+	userHandler := handlers.NewUserHandler(store, locales)
+	recommendationHandler := handlers.NewRecommendationHandler(store, recommender)
+	classroomHandler := handlers.NewClassroomHandler(store)
+	assignmentHandler := handlers.NewAssignmentHandler(store)
+	guardianHandler := handlers.NewGuardianHandler(store)
+	organizationHandler := handlers.NewOrganizationHandler(store)
+	exportHandler := handlers.NewExportHandler(store, exporter)
 
 	// 創建主路由器
 	router := mux.NewRouter()
+	methodNotAllowed := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, i18n.T(r.Context(), "Method not allowed"))
+	})
+	router.MethodNotAllowedHandler = methodNotAllowed
+	// 需要認證的子路由以空前綴匹配所有路徑，mux 會因此遺失方法不符的結果而回報 404，
+	// 故在找不到路由時以其他請求方法重新比對
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if matchesOtherMethod(router, r) {
+			methodNotAllowed(w, r)
+			return
+		}
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, i18n.T(r.Context(), "Not found"))
+	})
 	api := router.PathPrefix("/api").Subrouter()
 
 	// 公共路由
@@ -32,17 +63,96 @@ func SetupRoutes(config *configs.Config) *mux.Router {
 	// 需要認證的路由
 	authenticatedAPI := api.PathPrefix("").Subrouter()
 	authenticatedAPI.Use(middleware.AuthMiddleware(config))
+	authenticatedAPI.Use(middleware.ActiveUserMiddleware(store))
+	authenticatedAPI.Use(middleware.UserLocaleMiddleware(locales))
+
+	// 組織相關路由
+	authenticatedAPI.HandleFunc("/organizations", organizationHandler.CreateOrganization).Methods("POST")
+	authenticatedAPI.HandleFunc("/organizations/current", organizationHandler.GetCurrentOrganization).Methods("GET")
+
+	// 用戶設定相關路由
+	authenticatedAPI.HandleFunc("/users/{userId}/privacy", userHandler.UpdatePrivacy).Methods("PUT")
+	authenticatedAPI.HandleFunc("/users/{userId}/preferences", userHandler.UpdatePreferences).Methods("PUT")
+	authenticatedAPI.HandleFunc("/users/{userId}/role", userHandler.UpdateRole).Methods("PUT")
+	authenticatedAPI.HandleFunc("/users/{userId}/export", exportHandler.ExportUserData).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}", userHandler.DeleteAccount).Methods("DELETE")
+	authenticatedAPI.HandleFunc("/users/{userId}/deletion/cancel", userHandler.CancelAccountDeletion).Methods("POST")
+	authenticatedAPI.HandleFunc("/audit-log", userHandler.GetAuditLog).Methods("GET")
 
 	// 字元相關路由
 	authenticatedAPI.HandleFunc("/characters", characterHandler.GetCharacters).Methods("GET")
+	authenticatedAPI.HandleFunc("/characters", characterHandler.CreateCharacter).Methods("POST")
 	authenticatedAPI.HandleFunc("/characters/{id}", characterHandler.GetCharacterByID).Methods("GET")
+	authenticatedAPI.HandleFunc("/characters/{id}", characterHandler.UpdateCharacter).Methods("PUT")
+	authenticatedAPI.HandleFunc("/decks", characterHandler.GetDecks).Methods("GET")
+	authenticatedAPI.HandleFunc("/decks", characterHandler.CreateDeck).Methods("POST")
 
 	// 筆畫記錄相關路由
 	authenticatedAPI.HandleFunc("/strokes/record", strokeHandler.RecordStroke).Methods("POST")
+	authenticatedAPI.HandleFunc("/strokes/batch", strokeHandler.RecordStrokeBatch).Methods("POST")
 	authenticatedAPI.HandleFunc("/users/{userId}/stroke-records", strokeHandler.GetUserStrokeRecords).Methods("GET")
 
 	// 進度相關路由
 	authenticatedAPI.HandleFunc("/users/{userId}/progress", progressHandler.GetUserProgress).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}/progress/history", progressHandler.GetProgressHistory).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}/recommendations", recommendationHandler.GetRecommendations).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}/weaknesses", progressHandler.GetStrokeWeaknesses).Methods("GET")
+
+	// 每日目標與連續練習相關路由
+	authenticatedAPI.HandleFunc("/users/{userId}/goal", goalHandler.GetDailyGoalStatus).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}/goal", goalHandler.UpdateDailyGoal).Methods("PUT")
+	authenticatedAPI.HandleFunc("/users/{userId}/streak", goalHandler.GetStreak).Methods("GET")
+
+	// 成就相關路由
+	authenticatedAPI.HandleFunc("/users/{userId}/achievements", achievementHandler.GetUserAchievements).Methods("GET")
+
+	// 班級相關路由
+	authenticatedAPI.HandleFunc("/classes", classroomHandler.CreateClassroom).Methods("POST")
+	authenticatedAPI.HandleFunc("/classes", classroomHandler.GetMyClassrooms).Methods("GET")
+	authenticatedAPI.HandleFunc("/classes/join", classroomHandler.JoinClassroom).Methods("POST")
+	authenticatedAPI.HandleFunc("/classes/{classId}", classroomHandler.GetClassroom).Methods("GET")
+	authenticatedAPI.HandleFunc("/classes/{classId}/students", classroomHandler.GetRoster).Methods("GET")
+	authenticatedAPI.HandleFunc("/classes/{classId}/dashboard", classroomHandler.GetDashboard).Methods("GET")
+	authenticatedAPI.HandleFunc("/classes/{classId}/students/{userId}/progress", classroomHandler.GetStudentProgress).Methods("GET")
+	authenticatedAPI.HandleFunc("/classes/{classId}/students/{userId}/stroke-records", classroomHandler.GetStudentStrokeRecords).Methods("GET")
+
+	// 作業相關路由
+	authenticatedAPI.HandleFunc("/classes/{classId}/assignments", assignmentHandler.CreateAssignment).Methods("POST")
+	authenticatedAPI.HandleFunc("/classes/{classId}/assignments", assignmentHandler.GetClassAssignments).Methods("GET")
+	authenticatedAPI.HandleFunc("/assignments/{assignmentId}", assignmentHandler.GetAssignment).Methods("GET")
+	authenticatedAPI.HandleFunc("/assignments/{assignmentId}", assignmentHandler.UpdateAssignment).Methods("PUT")
+	authenticatedAPI.HandleFunc("/assignments/{assignmentId}", assignmentHandler.DeleteAssignment).Methods("DELETE")
+	authenticatedAPI.HandleFunc("/assignments/{assignmentId}/status", assignmentHandler.GetAssignmentStatus).Methods("GET")
+	authenticatedAPI.HandleFunc("/users/{userId}/assignments", assignmentHandler.GetUserAssignments).Methods("GET")
+
+	// 監護人相關路由
+	authenticatedAPI.HandleFunc("/guardian-links", guardianHandler.InviteChild).Methods("POST")
+	authenticatedAPI.HandleFunc("/guardian-links", guardianHandler.GetMyGuardianLinks).Methods("GET")
+	authenticatedAPI.HandleFunc("/guardian-links/{linkId}/approve", guardianHandler.ApproveGuardianLink).Methods("POST")
+	authenticatedAPI.HandleFunc("/guardian-links/{linkId}/reject", guardianHandler.RejectGuardianLink).Methods("POST")
+	authenticatedAPI.HandleFunc("/guardian-links/{linkId}", guardianHandler.DeleteGuardianLink).Methods("DELETE")
+
+	// 排行榜相關路由
+	authenticatedAPI.HandleFunc("/leaderboards", leaderboardHandler.GetLeaderboard).Methods("GET")
 
 	return router
 }
+
+// routeMethods 為比對方法不符時嘗試的請求方法
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// matchesOtherMethod 判斷請求路徑是否以其他請求方法註冊了路由
+func matchesOtherMethod(router *mux.Router, r *http.Request) bool {
+	for _, method := range routeMethods {
+		if method == r.Method {
+			continue
+		}
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil {
+			return true
+		}
+	}
+	return false
+}
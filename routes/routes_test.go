@@ -0,0 +1,254 @@
+// backend/routes/routes_test.go
+package routes
+
+import (
+	"backend/configs"
+	"backend/i18n"
+	"backend/models"
+	"backend/storage/memory"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testServer 以記憶體儲存啟動完整路由的測試服務器
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	locales, err := i18n.LoadDefault("en")
+	if err != nil {
+		t.Fatalf("LoadDefault: %v", err)
+	}
+	config := &configs.Config{JWTSecret: []byte("test"), JWTExpiryTime: time.Hour}
+	server := httptest.NewServer(SetupRoutes(config, memory.NewMemoryStorage(), locales))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// do 發送請求並將回應解碼至 out，返回狀態碼
+func do(t *testing.T, server *httptest.Server, method, path, token string, body, out interface{}) int {
+	t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, server.URL+path, &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: decoding response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// login 以預設管理員登入，返回用戶與令牌
+func login(t *testing.T, server *httptest.Server) (models.User, string) {
+	t.Helper()
+	var resp models.LoginResponse
+	if status := do(t, server, "POST", "/api/auth/login", "", models.LoginRequest{Username: "admin", Password: "password"}, &resp); status != http.StatusOK {
+		t.Fatalf("login status = %d", status)
+	}
+	return resp.User, resp.Token
+}
+
+// syncStrokes 同步一批筆畫並返回各筆結果
+func syncStrokes(t *testing.T, server *httptest.Server, token string, userID int, items ...models.BatchStrokeItem) []models.BatchStrokeResult {
+	t.Helper()
+	for i := range items {
+		items[i].Path = []models.Node{{X: 0, Y: 0}, {X: 10, Y: 10}}
+	}
+	var resp models.BatchStrokeResponse
+	if status := do(t, server, "POST", "/api/strokes/batch", token, models.BatchStrokeRequest{UserID: userID, Records: items}, &resp); status != http.StatusOK {
+		t.Fatalf("batch status = %d", status)
+	}
+	return resp.Results
+}
+
+// progressOf 讀取用戶單一字元的進度
+func progressOf(t *testing.T, server *httptest.Server, token string, userID, characterID int) models.CharacterProgress {
+	t.Helper()
+	var progress models.UserProgress
+	if status := do(t, server, "GET", fmt.Sprintf("/api/users/%d/progress", userID), token, nil, &progress); status != http.StatusOK {
+		t.Fatalf("progress status = %d", status)
+	}
+	return progress[characterID]
+}
+
+func TestStrokeBatchDeduplicatesClientIDs(t *testing.T) {
+	server := testServer(t)
+	user, token := login(t, server)
+	at := time.Now().Add(-time.Hour).UTC()
+
+	// 同一批次中重複的客戶端ID只建立一次
+	first := syncStrokes(t, server, token, user.ID,
+		models.BatchStrokeItem{ClientID: "a", ClientTimestamp: at, CharacterID: 1, Score: 0.5},
+		models.BatchStrokeItem{ClientID: "a", ClientTimestamp: at, CharacterID: 1, Score: 0.5},
+		models.BatchStrokeItem{ClientID: "b", ClientTimestamp: at.Add(time.Minute), CharacterID: 1, Score: 0.7},
+	)
+	if first[0].Status != models.BatchItemCreated || first[1].Status != models.BatchItemDuplicate || first[2].Status != models.BatchItemCreated {
+		t.Fatalf("first sync = %+v, want created, duplicate, created", first)
+	}
+	if first[1].RecordID != first[0].RecordID {
+		t.Fatalf("duplicate points at record %d, want %d", first[1].RecordID, first[0].RecordID)
+	}
+
+	// 重送整批時全部視為重複，並指向原本的記錄
+	again := syncStrokes(t, server, token, user.ID,
+		models.BatchStrokeItem{ClientID: "a", ClientTimestamp: at, CharacterID: 1, Score: 0.5},
+		models.BatchStrokeItem{ClientID: "b", ClientTimestamp: at.Add(time.Minute), CharacterID: 1, Score: 0.7},
+	)
+	for i, result := range again {
+		if result.Status != models.BatchItemDuplicate || result.RecordID != first[2*i].RecordID {
+			t.Errorf("resent item %d = %+v, want duplicate of record %d", i, result, first[2*i].RecordID)
+		}
+	}
+
+	if progress := progressOf(t, server, token, user.ID, 1); progress.Attempts != 2 {
+		t.Fatalf("attempts = %d after resending, want 2", progress.Attempts)
+	}
+}
+
+func TestStrokeBatchOlderRecordsKeepRecency(t *testing.T) {
+	server := testServer(t)
+	user, token := login(t, server)
+	latest := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	// 批次內依客戶端時間處理，與請求順序無關
+	syncStrokes(t, server, token, user.ID,
+		models.BatchStrokeItem{ClientID: "new", ClientTimestamp: latest, CharacterID: 1, Score: 0.9},
+		models.BatchStrokeItem{ClientID: "old", ClientTimestamp: latest.Add(-2 * time.Hour), CharacterID: 1, Score: 0.5},
+	)
+	progress := progressOf(t, server, token, user.ID, 1)
+	if want := 0.3*0.9 + 0.7*0.5; progress.RecentScore != want || !progress.LastPracticedAt.Equal(latest) {
+		t.Fatalf("after first sync: recent %v, last practised %v; want %v, %v", progress.RecentScore, progress.LastPracticedAt, want, latest)
+	}
+
+	// 之後同步更早的記錄只計入次數與平均，不覆蓋近期得分
+	syncStrokes(t, server, token, user.ID,
+		models.BatchStrokeItem{ClientID: "older", ClientTimestamp: latest.Add(-24 * time.Hour), CharacterID: 1, Score: 0.1},
+	)
+	after := progressOf(t, server, token, user.ID, 1)
+	if after.Attempts != 3 || after.AvgScore != (0.9+0.5+0.1)/3 {
+		t.Errorf("attempts %d, average %v; want 3, %v", after.Attempts, after.AvgScore, (0.9+0.5+0.1)/3)
+	}
+	if after.RecentScore != progress.RecentScore || !after.LastPracticedAt.Equal(latest) {
+		t.Errorf("older record changed recent %v -> %v, last practised %v -> %v",
+			progress.RecentScore, after.RecentScore, progress.LastPracticedAt, after.LastPracticedAt)
+	}
+}
+
+func TestStrokeRecordsPageOnlyWhenRequested(t *testing.T) {
+	server := testServer(t)
+	user, token := login(t, server)
+	at := time.Now().Add(-time.Hour).UTC()
+	var items []models.BatchStrokeItem
+	for i := 0; i < 3; i++ {
+		items = append(items, models.BatchStrokeItem{ClientID: fmt.Sprint(i), ClientTimestamp: at.Add(time.Duration(i) * time.Minute), CharacterID: 1, Score: 0.5})
+	}
+	syncStrokes(t, server, token, user.ID, items...)
+	path := fmt.Sprintf("/api/users/%d/stroke-records", user.ID)
+
+	// 未指定 limit 或 cursor 時返回所有記錄的陣列
+	var records []models.StrokeRecord
+	if status := do(t, server, "GET", path, token, nil, &records); status != http.StatusOK || len(records) != 3 {
+		t.Fatalf("unpaged records = %d, %d records; want 200 with 3", status, len(records))
+	}
+
+	var page models.StrokeRecordPage
+	if status := do(t, server, "GET", path+"?limit=2", token, nil, &page); status != http.StatusOK || len(page.Records) != 2 || page.NextCursor == "" {
+		t.Fatalf("first page = %d, %+v; want 2 records and a cursor", status, page)
+	}
+	var next models.StrokeRecordPage
+	if status := do(t, server, "GET", path+"?cursor="+page.NextCursor, token, nil, &next); status != http.StatusOK || len(next.Records) != 1 || next.NextCursor != "" {
+		t.Fatalf("second page = %d, %+v; want the last record", status, next)
+	}
+	if status := do(t, server, "GET", path+"?cursor=not-a-cursor", token, nil, nil); status != http.StatusBadRequest {
+		t.Fatalf("invalid cursor = %d, want 400", status)
+	}
+}
+
+func TestRegisterCreatesStudentsAndAdminPromotesTeachers(t *testing.T) {
+	server := testServer(t)
+	admin, adminToken := login(t, server)
+
+	register := models.RegisterRequest{Username: "mia", Password: "pw", Role: models.RoleTeacher}
+	if status := do(t, server, "POST", "/api/auth/register", "", register, nil); status != http.StatusBadRequest {
+		t.Fatalf("registering as a teacher = %d, want 400", status)
+	}
+	register.Role = ""
+	reserved := models.RegisterRequest{Username: "Deleted-User-7", Password: "pw"}
+	if status := do(t, server, "POST", "/api/auth/register", "", reserved, nil); status != http.StatusBadRequest {
+		t.Fatalf("registering a reserved username = %d, want 400", status)
+	}
+	var student models.LoginResponse
+	if status := do(t, server, "POST", "/api/auth/register", "", register, &student); status != http.StatusOK || student.User.Role != models.RoleStudent {
+		t.Fatalf("register = %d, %+v; want a student", status, student.User)
+	}
+
+	classroom := models.CreateClassroomRequest{Name: "3C"}
+	if status := do(t, server, "POST", "/api/classes", student.Token, classroom, nil); status != http.StatusForbidden {
+		t.Fatalf("student creating a class = %d, want 403", status)
+	}
+
+	// 只有管理員可以變更角色，管理員本身的角色不可變更
+	rolePath := fmt.Sprintf("/api/users/%d/role", student.User.ID)
+	promote := models.RoleUpdateRequest{Role: models.RoleTeacher}
+	if status := do(t, server, "PUT", rolePath, student.Token, promote, nil); status != http.StatusForbidden {
+		t.Fatalf("student promoting themselves = %d, want 403", status)
+	}
+	if status := do(t, server, "PUT", fmt.Sprintf("/api/users/%d/role", admin.ID), adminToken, promote, nil); status != http.StatusConflict {
+		t.Fatalf("changing the admin's role = %d, want 409", status)
+	}
+	var promoted models.User
+	if status := do(t, server, "PUT", rolePath, adminToken, promote, &promoted); status != http.StatusOK || promoted.Role != models.RoleTeacher {
+		t.Fatalf("promote = %d, %+v; want a teacher", status, promoted)
+	}
+
+	// 角色變更後原本的令牌即可使用老師的權限
+	if status := do(t, server, "POST", "/api/classes", student.Token, classroom, nil); status >= 300 {
+		t.Fatalf("teacher creating a class = %d, want success", status)
+	}
+
+	var entries []models.AuditEntry
+	if status := do(t, server, "GET", "/api/audit-log", adminToken, nil, &entries); status != http.StatusOK ||
+		len(entries) != 1 || entries[0].Action != models.AuditRoleChanged || entries[0].TargetUserID != student.User.ID {
+		t.Fatalf("audit log = %d, %+v; want one role change", status, entries)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	server := testServer(t)
+	_, token := login(t, server)
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{"DELETE", "/api/auth/login", http.StatusMethodNotAllowed},
+		{"PATCH", "/api/users/1/goal", http.StatusMethodNotAllowed},
+		{"POST", "/api/classes/1", http.StatusMethodNotAllowed},
+		{"GET", "/api/nope", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if status := do(t, server, tt.method, tt.path, token, nil, nil); status != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.method, tt.path, status, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// backend/storage/apply.go
+package storage
+
+import (
+	"backend/models"
+	"context"
+)
+
+// ApplyStrokeRecord 將已儲存的筆畫記錄套用到組織內用戶的進度、當日統計與連續天數
+func ApplyStrokeRecord(ctx context.Context, s Storage, orgID int, record models.StrokeRecord) error {
+	if err := saveDueProgress(ctx, s, orgID, record); err != nil {
+		return err
+	}
+
+	err := s.UpdateUserProgress(ctx, orgID, record.UserID, record.CharacterID, record.StrokeIndex, record.Score, record.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	goal, err := s.GetDailyGoal(ctx, orgID, record.UserID)
+	if err != nil {
+		return err
+	}
+	return s.RecordDailyActivity(ctx, orgID, record.UserID, record.CharacterID, goal.LocalDate(record.CreatedAt), record.CreatedAt)
+}
+
+// saveDueProgress 在套用作業到期後的第一筆練習前，保存學生在作業字元上的進度
+// 在此之前沒有到期後的練習，目前的進度即為到期時的進度。
+// 只檢查已到期的作業，並逐一查詢該學生是否已保存，不讀取全班的到期進度。
+func saveDueProgress(ctx context.Context, s Storage, orgID int, record models.StrokeRecord) error {
+	classrooms, err := s.GetClassroomsByStudentID(ctx, orgID, record.UserID)
+	if err != nil {
+		return err
+	}
+
+	var progress models.UserProgress
+	for _, classroom := range classrooms {
+		assignments, err := s.GetAssignmentsByClassID(ctx, orgID, classroom.ID)
+		if err != nil {
+			return err
+		}
+		for _, assignment := range assignments {
+			if !record.CreatedAt.After(assignment.DueAt) {
+				continue
+			}
+			saved, err := s.HasAssignmentDueProgress(ctx, orgID, assignment.ID, record.UserID)
+			if err != nil {
+				return err
+			}
+			if saved {
+				continue
+			}
+
+			if progress == nil {
+				if progress, err = s.GetUserProgress(ctx, orgID, record.UserID); err != nil {
+					return err
+				}
+			}
+			due := models.UserProgress{}
+			for _, characterID := range assignment.CharacterIDs {
+				if charProgress, exists := progress[characterID]; exists {
+					due[characterID] = charProgress
+				}
+			}
+			if _, err := s.SaveAssignmentDueProgress(ctx, assignment.ID, record.UserID, due); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
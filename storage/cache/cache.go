@@ -0,0 +1,189 @@
+// backend/storage/cache/cache.go
+
+// Package cache 為 storage.Storage 加上字元資料的讀取快取
+//
+// 字元資料讀多寫少，快取以 TTL 到期，並在經由本層建立或更新字元時立即失效。
+// 其他程序直接寫入底層儲存時，最多在 TTL 內讀到舊資料。
+package cache
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"sync"
+	"time"
+)
+
+// characterKey 字元詳情的快取鍵，同一字元在不同組織下的可見性不同
+type characterKey struct {
+	orgID int
+	id    int
+}
+
+// listEntry 組織可用字元列表的快取項目
+type listEntry struct {
+	characters []models.CharacterPreview
+	expiresAt  time.Time
+}
+
+// detailEntry 字元詳情的快取項目
+type detailEntry struct {
+	character models.Character
+	expiresAt time.Time
+}
+
+// Storage 快取 GetCharacters 與 GetCharacterByID 的儲存裝飾器，其餘方法直接交給底層儲存
+type Storage struct {
+	storage.Storage
+	ttl time.Duration
+	now func() time.Time
+
+	mu         sync.Mutex
+	generation uint64 // 每次失效時遞增，讀取期間發生失效的結果不寫入快取
+	lists      map[int]listEntry
+	details    map[characterKey]detailEntry
+}
+
+// New 以指定的 TTL 包裝底層儲存，TTL 不大於零時不快取
+func New(inner storage.Storage, ttl time.Duration) *Storage {
+	return &Storage{
+		Storage: inner,
+		ttl:     ttl,
+		now:     time.Now,
+		lists:   make(map[int]listEntry),
+		details: make(map[characterKey]detailEntry),
+	}
+}
+
+// GetCharacters 獲取組織可用的所有字元，優先使用快取
+func (s *Storage) GetCharacters(ctx context.Context, orgID int) ([]models.CharacterPreview, error) {
+	s.mu.Lock()
+	entry, ok := s.lists[orgID]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && s.now().Before(entry.expiresAt) {
+		return append([]models.CharacterPreview{}, entry.characters...), nil
+	}
+
+	characters, err := s.Storage.GetCharacters(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.ttl > 0 && s.generation == generation {
+		s.lists[orgID] = listEntry{
+			characters: append([]models.CharacterPreview{}, characters...),
+			expiresAt:  s.now().Add(s.ttl),
+		}
+	}
+	s.mu.Unlock()
+	return characters, nil
+}
+
+// GetCharacterByID 根據ID獲取組織可用的字元詳情，優先使用快取
+func (s *Storage) GetCharacterByID(ctx context.Context, orgID, id int) (*models.Character, error) {
+	key := characterKey{orgID: orgID, id: id}
+	s.mu.Lock()
+	entry, ok := s.details[key]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && s.now().Before(entry.expiresAt) {
+		character := entry.character
+		return &character, nil
+	}
+
+	character, err := s.Storage.GetCharacterByID(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.ttl > 0 && s.generation == generation {
+		s.details[key] = detailEntry{character: *character, expiresAt: s.now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return character, nil
+}
+
+// CreateCharacter 創建組織的自訂字元並使該組織的字元列表失效
+func (s *Storage) CreateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	created, err := s.Storage.CreateCharacter(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(characterKey{orgID: created.OrgID, id: created.ID})
+	return created, nil
+}
+
+// UpdateCharacter 更新組織的自訂字元並使相關快取失效
+func (s *Storage) UpdateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	updated, err := s.Storage.UpdateCharacter(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(characterKey{orgID: updated.OrgID, id: updated.ID})
+	return updated, nil
+}
+
+// RunInTx 在底層儲存的交易中執行 fn
+// 交易內的讀取不經過快取以看見交易自身的寫入，寫入的字元在提交後才使快取失效。
+func (s *Storage) RunInTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	var written []characterKey
+	err := s.Storage.RunInTx(ctx, func(inner storage.Storage) error {
+		return fn(&txStorage{Storage: inner, written: &written})
+	})
+	if err != nil {
+		return err
+	}
+	s.invalidate(written...)
+	return nil
+}
+
+// invalidate 使指定字元的詳情與其組織的字元列表失效
+func (s *Storage) invalidate(keys ...characterKey) {
+	if len(keys) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation++
+	for _, key := range keys {
+		delete(s.lists, key.orgID)
+		delete(s.details, key)
+	}
+}
+
+// txStorage 交易中的儲存，記錄寫入的字元以便提交後使快取失效
+type txStorage struct {
+	storage.Storage
+	written *[]characterKey
+}
+
+// RunInTx 巢狀交易併入外層交易，沿用同一份寫入記錄
+func (t *txStorage) RunInTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	return t.Storage.RunInTx(ctx, func(inner storage.Storage) error {
+		return fn(&txStorage{Storage: inner, written: t.written})
+	})
+}
+
+// CreateCharacter 在交易中創建字元並記錄寫入
+func (t *txStorage) CreateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	created, err := t.Storage.CreateCharacter(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+	*t.written = append(*t.written, characterKey{orgID: created.OrgID, id: created.ID})
+	return created, nil
+}
+
+// UpdateCharacter 在交易中更新字元並記錄寫入
+func (t *txStorage) UpdateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	updated, err := t.Storage.UpdateCharacter(ctx, character)
+	if err != nil {
+		return nil, err
+	}
+	*t.written = append(*t.written, characterKey{orgID: updated.OrgID, id: updated.ID})
+	return updated, nil
+}
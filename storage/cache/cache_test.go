@@ -0,0 +1,154 @@
+// backend/storage/cache/cache_test.go
+package cache
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/storage/memory"
+	"backend/storage/storagetest"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingStorage 計算底層字元查詢的次數
+type countingStorage struct {
+	storage.Storage
+	lists   int
+	details int
+}
+
+func (c *countingStorage) GetCharacters(ctx context.Context, orgID int) ([]models.CharacterPreview, error) {
+	c.lists++
+	return c.Storage.GetCharacters(ctx, orgID)
+}
+
+func (c *countingStorage) GetCharacterByID(ctx context.Context, orgID, id int) (*models.Character, error) {
+	c.details++
+	return c.Storage.GetCharacterByID(ctx, orgID, id)
+}
+
+// newCounting 建立以計數儲存為底層、時鐘可控的快取
+func newCounting(ttl time.Duration) (*Storage, *countingStorage, *time.Time) {
+	inner := &countingStorage{Storage: memory.NewMemoryStorage()}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New(inner, ttl)
+	s.now = func() time.Time { return clock }
+	return s, inner, &clock
+}
+
+// customCharacter 測試用的自訂字元
+func customCharacter(name string) models.Character {
+	return models.Character{
+		Name:       name,
+		OrgID:      models.DefaultOrganizationID,
+		StrokeData: []models.Stroke{{Nodes: []models.Node{{X: 0, Y: 0}, {X: 10, Y: 0}}}},
+	}
+}
+
+func TestCachesUntilTTLExpires(t *testing.T) {
+	ctx := context.Background()
+	s, inner, clock := newCounting(time.Minute)
+	orgID := models.DefaultOrganizationID
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.GetCharacters(ctx, orgID); err != nil {
+			t.Fatalf("GetCharacters: %v", err)
+		}
+		if _, err := s.GetCharacterByID(ctx, orgID, 1); err != nil {
+			t.Fatalf("GetCharacterByID: %v", err)
+		}
+	}
+	if inner.lists != 1 || inner.details != 1 {
+		t.Fatalf("underlying reads = %d lists, %d details; want 1 each", inner.lists, inner.details)
+	}
+
+	*clock = clock.Add(time.Minute)
+	s.GetCharacters(ctx, orgID)
+	s.GetCharacterByID(ctx, orgID, 1)
+	if inner.lists != 2 || inner.details != 2 {
+		t.Fatalf("underlying reads after TTL = %d lists, %d details; want 2 each", inner.lists, inner.details)
+	}
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newCounting(time.Hour)
+	orgID := models.DefaultOrganizationID
+
+	before, _ := s.GetCharacters(ctx, orgID)
+	created, err := s.CreateCharacter(ctx, customCharacter("甲"))
+	if err != nil {
+		t.Fatalf("CreateCharacter: %v", err)
+	}
+	after, _ := s.GetCharacters(ctx, orgID)
+	if len(after) != len(before)+1 {
+		t.Fatalf("characters after create = %d, want %d", len(after), len(before)+1)
+	}
+
+	s.GetCharacterByID(ctx, orgID, created.ID)
+	update := customCharacter("乙")
+	update.ID = created.ID
+	if _, err := s.UpdateCharacter(ctx, update); err != nil {
+		t.Fatalf("UpdateCharacter: %v", err)
+	}
+	got, err := s.GetCharacterByID(ctx, orgID, created.ID)
+	if err != nil || got.Name != "乙" {
+		t.Fatalf("GetCharacterByID after update = %v, %v; want 乙", got, err)
+	}
+}
+
+func TestTransactionInvalidatesOnCommitOnly(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newCounting(time.Hour)
+	orgID := models.DefaultOrganizationID
+	before, _ := s.GetCharacters(ctx, orgID)
+
+	failure := errors.New("fail")
+	err := s.RunInTx(ctx, func(tx storage.Storage) error {
+		if _, err := tx.CreateCharacter(ctx, customCharacter("丙")); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("RunInTx = %v, want failure", err)
+	}
+	if got, _ := s.GetCharacters(ctx, orgID); len(got) != len(before) {
+		t.Fatalf("characters after rollback = %d, want %d", len(got), len(before))
+	}
+
+	err = s.RunInTx(ctx, func(tx storage.Storage) error {
+		_, err := tx.CreateCharacter(ctx, customCharacter("丁"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("RunInTx: %v", err)
+	}
+	if got, _ := s.GetCharacters(ctx, orgID); len(got) != len(before)+1 {
+		t.Fatalf("characters after commit = %d, want %d", len(got), len(before)+1)
+	}
+}
+
+func TestReturnedValuesAreCopies(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newCounting(time.Hour)
+	orgID := models.DefaultOrganizationID
+
+	characters, _ := s.GetCharacters(ctx, orgID)
+	characters[0].Name = "changed"
+	character, _ := s.GetCharacterByID(ctx, orgID, 1)
+	character.Name = "changed"
+
+	if again, _ := s.GetCharacters(ctx, orgID); again[0].Name == "changed" {
+		t.Fatal("modifying a returned list changed the cache")
+	}
+	if again, _ := s.GetCharacterByID(ctx, orgID, 1); again.Name == "changed" {
+		t.Fatal("modifying a returned character changed the cache")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Storage { return New(memory.NewMemoryStorage(), time.Hour) })
+}
@@ -0,0 +1,30 @@
+// backend/storage/cursor.go
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// EncodeCursor 將最後一筆記錄ID編碼為不透明的分頁游標
+func EncodeCursor(recordID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(recordID)))
+}
+
+// DecodeCursor 解析分頁游標，空字串表示從頭開始，無法解析時返回包裝 ErrInvalid 的錯誤
+func DecodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	recordID, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return 0, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	return recordID, nil
+}
@@ -0,0 +1,14 @@
+// backend/storage/errors.go
+package storage
+
+import "errors"
+
+// 儲存實作返回的錯誤類型，呼叫者以 errors.Is 判斷
+var (
+	// ErrNotFound 查詢的資料不存在或不屬於指定的組織
+	ErrNotFound = errors.New("not found")
+	// ErrConflict 資料與既有資料衝突，例如用戶名或代碼重複
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid 查詢參數無效，例如無法解析的分頁游標
+	ErrInvalid = errors.New("invalid argument")
+)
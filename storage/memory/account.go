@@ -0,0 +1,147 @@
+// backend/storage/memory/account.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetUsersDueForDeletion 獲取所有組織中在指定時間前申請刪除、尚未清除的用戶
+func (s *MemoryStorage) GetUsersDueForDeletion(ctx context.Context, requestedBefore time.Time) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range s.users {
+		if user.DeletedAt == nil && user.DeletionRequestedAt != nil && user.DeletionRequestedAt.Before(requestedBefore) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// PurgeUser 刪除用戶的筆畫記錄、進度、練習統計、成就、班級成員與監護人連結，並匿名化帳號
+func (s *MemoryStorage) PurgeUser(ctx context.Context, orgID, userID int, at time.Time) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.PurgeUser(ctx, orgID, userID, at)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := -1
+	for i, user := range s.users {
+		if user.ID == userID && user.OrgID == orgID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("user with ID %d: %w", userID, storage.ErrNotFound)
+	}
+
+	saveEntry(s.undo, s.strokeRecords, userID)
+	saveEntry(s.undo, s.clientRecordIDs, userID)
+	saveEntry(s.undo, s.strokeStats, userID)
+	saveEntry(s.undo, s.userProgress, userID)
+	saveEntry(s.undo, s.dailyGoals, userID)
+	saveEntry(s.undo, s.dailyActivity, userID)
+	saveEntry(s.undo, s.streaks, userID)
+	saveEntry(s.undo, s.achievements, userID)
+	saveEntry(s.undo, s.history, userID)
+	delete(s.strokeRecords, userID)
+	delete(s.clientRecordIDs, userID)
+	delete(s.strokeStats, userID)
+	delete(s.userProgress, userID)
+	delete(s.dailyGoals, userID)
+	delete(s.dailyActivity, userID)
+	delete(s.streaks, userID)
+	delete(s.achievements, userID)
+	delete(s.history, userID)
+	for _, aggregates := range s.aggregates {
+		saveEntry(s.undo, aggregates, userID)
+		delete(aggregates, userID)
+	}
+	for _, students := range s.dueProgress {
+		saveEntry(s.undo, students, userID)
+		delete(students, userID)
+	}
+
+	for classID, members := range s.classMembers {
+		saveEntry(s.undo, s.classMembers, classID)
+		remaining := []models.ClassMembership{}
+		for _, member := range members {
+			if member.UserID != userID {
+				remaining = append(remaining, member)
+			}
+		}
+		s.classMembers[classID] = remaining
+	}
+
+	saveValue(s.undo, &s.guardianLinks)
+	links := []models.GuardianLink{}
+	for _, link := range s.guardianLinks {
+		if link.GuardianID != userID && link.ChildID != userID {
+			links = append(links, link)
+		}
+	}
+	s.guardianLinks = links
+
+	// 匿名用戶名與組織內其他用戶重複時加上序號
+	username := models.AnonymizedUsername(userID, 1)
+	for attempt := 2; s.usernameTaken(orgID, username, userID); attempt++ {
+		username = models.AnonymizedUsername(userID, attempt)
+	}
+	saveValue(s.undo, &s.users[index])
+	s.users[index].Anonymize(username, at)
+	return s.record("PurgeUser", orgID, userID, at)
+}
+
+// usernameTaken 判斷組織內是否已有其他用戶使用此用戶名，呼叫者需持有鎖
+func (s *MemoryStorage) usernameTaken(orgID int, username string, exceptID int) bool {
+	for _, user := range s.users {
+		if user.OrgID == orgID && user.ID != exceptID && user.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAuditEntry 新增稽核記錄
+func (s *MemoryStorage) CreateAuditEntry(ctx context.Context, entry models.AuditEntry) (*models.AuditEntry, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.AuditEntry, error) {
+			return tx.CreateAuditEntry(ctx, entry)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saveValue(s.undo, &s.auditEntries)
+	entry.ID = len(s.auditEntries) + 1
+	s.auditEntries = append(s.auditEntries, entry)
+	if err := s.record("CreateAuditEntry", entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetAuditEntries 獲取組織的所有稽核記錄
+func (s *MemoryStorage) GetAuditEntries(ctx context.Context, orgID int) ([]models.AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := []models.AuditEntry{}
+	for _, entry := range s.auditEntries {
+		if entry.OrgID == orgID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
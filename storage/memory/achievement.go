@@ -0,0 +1,53 @@
+// backend/storage/memory/achievement.go
+package memory
+
+import (
+	"backend/models"
+	"context"
+	"time"
+)
+
+// GetUserAchievements 獲取用戶已解鎖的成就
+func (s *MemoryStorage) GetUserAchievements(ctx context.Context, orgID, userID int) ([]models.UserAchievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return nil, err
+	}
+
+	return append([]models.UserAchievement{}, s.achievements[userID]...), nil
+}
+
+// UnlockAchievement 解鎖成就，已解鎖時返回 false
+func (s *MemoryStorage) UnlockAchievement(ctx context.Context, orgID, userID int, achievementID string, at time.Time) (bool, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (bool, error) {
+			return tx.UnlockAchievement(ctx, orgID, userID, achievementID, at)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return false, err
+	}
+
+	for _, unlocked := range s.achievements[userID] {
+		if unlocked.AchievementID == achievementID {
+			return false, nil
+		}
+	}
+
+	saveEntry(s.undo, s.achievements, userID)
+	s.achievements[userID] = append(s.achievements[userID], models.UserAchievement{
+		UserID:        userID,
+		AchievementID: achievementID,
+		UnlockedAt:    at,
+	})
+	if err := s.record("UnlockAchievement", orgID, userID, achievementID, at); err != nil {
+		return false, err
+	}
+	return true, nil
+}
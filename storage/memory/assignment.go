@@ -0,0 +1,227 @@
+// backend/storage/memory/assignment.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"fmt"
+)
+
+// CreateAssignment 創建作業
+func (s *MemoryStorage) CreateAssignment(ctx context.Context, assignment models.Assignment) (*models.Assignment, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Assignment, error) {
+			return tx.CreateAssignment(ctx, assignment)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saveValue(s.undo, &s.assignmentSeq)
+	saveValue(s.undo, &s.assignments)
+	s.assignmentSeq++
+	assignment.ID = s.assignmentSeq
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = s.now()
+	}
+	assignment.CharacterIDs = append([]int{}, assignment.CharacterIDs...)
+
+	s.assignments = append(s.assignments, assignment)
+	if err := s.recordAt("CreateAssignment", assignment.CreatedAt, assignment); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// GetAssignmentByID 根據ID獲取組織內班級的作業
+func (s *MemoryStorage) GetAssignmentByID(ctx context.Context, orgID, id int) (*models.Assignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, assignment := range s.assignments {
+		if assignment.ID != id {
+			continue
+		}
+		if _, err := s.classroomByID(orgID, assignment.ClassID); err != nil {
+			break
+		}
+		return &assignment, nil
+	}
+	return nil, fmt.Errorf("assignment with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// GetAssignmentsByClassID 獲取班級的所有作業
+func (s *MemoryStorage) GetAssignmentsByClassID(ctx context.Context, orgID, classID int) ([]models.Assignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.classroomByID(orgID, classID); err != nil {
+		return nil, err
+	}
+
+	assignments := []models.Assignment{}
+	for _, assignment := range s.assignments {
+		if assignment.ClassID == classID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+// UpdateAssignment 更新作業內容，班級與建立時間不變
+func (s *MemoryStorage) UpdateAssignment(ctx context.Context, assignment models.Assignment) (*models.Assignment, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Assignment, error) {
+			return tx.UpdateAssignment(ctx, assignment)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.assignments {
+		if existing.ID == assignment.ID {
+			assignment.ClassID = existing.ClassID
+			assignment.CreatedAt = existing.CreatedAt
+			assignment.CharacterIDs = append([]int{}, assignment.CharacterIDs...)
+			saveValue(s.undo, &s.assignments[i])
+			s.assignments[i] = assignment
+
+			// 到期時間或字元變更後，已保存的到期進度不再適用
+			if !assignment.DueAt.Equal(existing.DueAt) || !sameIDs(assignment.CharacterIDs, existing.CharacterIDs) {
+				saveEntry(s.undo, s.dueProgress, assignment.ID)
+				delete(s.dueProgress, assignment.ID)
+			}
+			if err := s.record("UpdateAssignment", assignment); err != nil {
+				return nil, err
+			}
+			return &assignment, nil
+		}
+	}
+	return nil, fmt.Errorf("assignment with ID %d: %w", assignment.ID, storage.ErrNotFound)
+}
+
+// DeleteAssignment 刪除作業
+func (s *MemoryStorage) DeleteAssignment(ctx context.Context, orgID, id int) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.DeleteAssignment(ctx, orgID, id)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, assignment := range s.assignments {
+		if assignment.ID == id {
+			if _, err := s.classroomByID(orgID, assignment.ClassID); err != nil {
+				break
+			}
+			saveSlice(s.undo, &s.assignments)
+			s.assignments = append(s.assignments[:i], s.assignments[i+1:]...)
+			saveEntry(s.undo, s.dueProgress, id)
+			delete(s.dueProgress, id)
+			return s.record("DeleteAssignment", orgID, id)
+		}
+	}
+	return fmt.Errorf("assignment with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// SaveAssignmentDueProgress 保存學生在作業到期時的進度，已保存過時不覆蓋
+func (s *MemoryStorage) SaveAssignmentDueProgress(ctx context.Context, assignmentID, userID int, progress models.UserProgress) (bool, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (bool, error) {
+			return tx.SaveAssignmentDueProgress(ctx, assignmentID, userID, progress)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists := false
+	for _, assignment := range s.assignments {
+		if assignment.ID == assignmentID {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return false, fmt.Errorf("assignment with ID %d: %w", assignmentID, storage.ErrNotFound)
+	}
+
+	students, exists := s.dueProgress[assignmentID]
+	if !exists {
+		saveEntry(s.undo, s.dueProgress, assignmentID)
+		students = make(map[int]models.UserProgress)
+		s.dueProgress[assignmentID] = students
+	}
+	if _, saved := students[userID]; saved {
+		return false, nil
+	}
+
+	copied := make(models.UserProgress, len(progress))
+	for characterID, charProgress := range progress {
+		copied[characterID] = charProgress
+	}
+	saveEntry(s.undo, students, userID)
+	students[userID] = copied
+	if err := s.record("SaveAssignmentDueProgress", assignmentID, userID, copied); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetAssignmentDueProgress 獲取組織內作業已保存的到期進度，學生ID對應進度
+func (s *MemoryStorage) GetAssignmentDueProgress(ctx context.Context, orgID, assignmentID int) (map[int]models.UserProgress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, assignment := range s.assignments {
+		if assignment.ID != assignmentID {
+			continue
+		}
+		if _, err := s.classroomByID(orgID, assignment.ClassID); err != nil {
+			break
+		}
+
+		students := make(map[int]models.UserProgress, len(s.dueProgress[assignmentID]))
+		for userID, progress := range s.dueProgress[assignmentID] {
+			students[userID] = progress
+		}
+		return students, nil
+	}
+	return nil, fmt.Errorf("assignment with ID %d: %w", assignmentID, storage.ErrNotFound)
+}
+
+// HasAssignmentDueProgress 檢查是否已保存學生在組織內作業的到期進度
+func (s *MemoryStorage) HasAssignmentDueProgress(ctx context.Context, orgID, assignmentID, userID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, assignment := range s.assignments {
+		if assignment.ID != assignmentID {
+			continue
+		}
+		if _, err := s.classroomByID(orgID, assignment.ClassID); err != nil {
+			break
+		}
+		_, saved := s.dueProgress[assignmentID][userID]
+		return saved, nil
+	}
+	return false, fmt.Errorf("assignment with ID %d: %w", assignmentID, storage.ErrNotFound)
+}
+
+// sameIDs 檢查兩組ID是否依序相同
+func sameIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// backend/storage/memory/classroom.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"fmt"
+)
+
+// CreateClassroom 創建班級
+func (s *MemoryStorage) CreateClassroom(ctx context.Context, classroom models.Classroom) (*models.Classroom, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Classroom, error) {
+			return tx.CreateClassroom(ctx, classroom)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 檢查加入代碼是否已存在
+	for _, existing := range s.classrooms {
+		if existing.EnrolmentCode == classroom.EnrolmentCode {
+			return nil, fmt.Errorf("enrolment code: %w", storage.ErrConflict)
+		}
+	}
+
+	saveValue(s.undo, &s.classrooms)
+	classroom.ID = len(s.classrooms) + 1
+	if classroom.CreatedAt.IsZero() {
+		classroom.CreatedAt = s.now()
+	}
+	s.classrooms = append(s.classrooms, classroom)
+	if err := s.recordAt("CreateClassroom", classroom.CreatedAt, classroom); err != nil {
+		return nil, err
+	}
+	return &classroom, nil
+}
+
+// GetClassroomByID 根據ID獲取組織內的班級
+func (s *MemoryStorage) GetClassroomByID(ctx context.Context, orgID, id int) (*models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.classroomByID(orgID, id)
+}
+
+// classroomByID 根據ID查找組織內的班級，呼叫者需持有鎖
+func (s *MemoryStorage) classroomByID(orgID, id int) (*models.Classroom, error) {
+	for _, classroom := range s.classrooms {
+		if classroom.ID == id && classroom.OrgID == orgID {
+			return &classroom, nil
+		}
+	}
+	return nil, fmt.Errorf("classroom with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// GetClassroomByCode 根據加入代碼獲取組織內的班級
+func (s *MemoryStorage) GetClassroomByCode(ctx context.Context, orgID int, code string) (*models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, classroom := range s.classrooms {
+		if classroom.EnrolmentCode == code && classroom.OrgID == orgID {
+			return &classroom, nil
+		}
+	}
+	return nil, fmt.Errorf("classroom: %w", storage.ErrNotFound)
+}
+
+// GetClassroomsByTeacherID 獲取老師任教的班級
+func (s *MemoryStorage) GetClassroomsByTeacherID(ctx context.Context, orgID, teacherID int) ([]models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	classrooms := []models.Classroom{}
+	for _, classroom := range s.classrooms {
+		if classroom.TeacherID == teacherID && classroom.OrgID == orgID {
+			classrooms = append(classrooms, classroom)
+		}
+	}
+	return classrooms, nil
+}
+
+// GetClassroomsByStudentID 獲取學生加入的班級
+func (s *MemoryStorage) GetClassroomsByStudentID(ctx context.Context, orgID, userID int) ([]models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	classrooms := []models.Classroom{}
+	for _, classroom := range s.classrooms {
+		if classroom.OrgID != orgID {
+			continue
+		}
+		for _, member := range s.classMembers[classroom.ID] {
+			if member.UserID == userID {
+				classrooms = append(classrooms, classroom)
+				break
+			}
+		}
+	}
+	return classrooms, nil
+}
+
+// AddClassMember 將學生加入班級
+func (s *MemoryStorage) AddClassMember(ctx context.Context, classID, userID int) (*models.ClassMembership, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.ClassMembership, error) {
+			return tx.AddClassMember(ctx, classID, userID)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, member := range s.classMembers[classID] {
+		if member.UserID == userID {
+			return nil, fmt.Errorf("class membership: %w", storage.ErrConflict)
+		}
+	}
+
+	membership := models.ClassMembership{
+		ClassID:  classID,
+		UserID:   userID,
+		JoinedAt: s.now(),
+	}
+	saveEntry(s.undo, s.classMembers, classID)
+	s.classMembers[classID] = append(s.classMembers[classID], membership)
+	if err := s.recordAt("AddClassMember", membership.JoinedAt, classID, userID); err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// GetClassMembers 獲取班級的學生
+func (s *MemoryStorage) GetClassMembers(ctx context.Context, orgID, classID int) ([]models.ClassMembership, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.classroomByID(orgID, classID); err != nil {
+		return nil, err
+	}
+
+	return append([]models.ClassMembership{}, s.classMembers[classID]...), nil
+}
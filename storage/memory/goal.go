@@ -0,0 +1,122 @@
+// backend/storage/memory/goal.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"time"
+)
+
+// GetDailyGoal 獲取用戶的每日目標，未設定時返回預設目標
+func (s *MemoryStorage) GetDailyGoal(ctx context.Context, orgID, userID int) (models.DailyGoal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return models.DailyGoal{}, err
+	}
+
+	goal, exists := s.dailyGoals[userID]
+	if !exists {
+		return models.DefaultDailyGoal(userID), nil
+	}
+	return goal, nil
+}
+
+// SetDailyGoal 設定用戶的每日目標
+func (s *MemoryStorage) SetDailyGoal(ctx context.Context, goal models.DailyGoal) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.SetDailyGoal(ctx, goal)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saveEntry(s.undo, s.dailyGoals, goal.UserID)
+	s.dailyGoals[goal.UserID] = goal
+	return s.record("SetDailyGoal", goal)
+}
+
+// GetDailyActivity 獲取用戶某一天的練習統計
+func (s *MemoryStorage) GetDailyActivity(ctx context.Context, orgID, userID int, date string) (models.DailyActivity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return models.DailyActivity{}, err
+	}
+
+	activity, exists := s.dailyActivity[userID][date]
+	if !exists {
+		return models.DailyActivity{Date: date, CharacterIDs: []int{}}, nil
+	}
+	activity.CharacterIDs = append([]int{}, activity.CharacterIDs...)
+	return activity, nil
+}
+
+// RecordDailyActivity 記錄一次練習並更新當日統計與連續天數
+func (s *MemoryStorage) RecordDailyActivity(ctx context.Context, orgID, userID, characterID int, date string, at time.Time) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.RecordDailyActivity(ctx, orgID, userID, characterID, date, at)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return err
+	}
+
+	days, exists := s.dailyActivity[userID]
+	if !exists {
+		saveEntry(s.undo, s.dailyActivity, userID)
+		days = make(map[string]models.DailyActivity)
+		s.dailyActivity[userID] = days
+	}
+
+	activity, practiced := days[date]
+	if !practiced {
+		activity = models.DailyActivity{Date: date}
+	}
+	activity.AddPractice(characterID, at)
+	saveEntry(s.undo, days, date)
+	days[date] = activity
+
+	streak := s.streaks[userID]
+	if !practiced && streak.LastPracticeDate != "" && date < streak.LastPracticeDate {
+		// 離線同步補登較早的日期，需重新計算整段連續天數
+		dates := make([]string, 0, len(days))
+		for day := range days {
+			dates = append(dates, day)
+		}
+		streak = models.StreakFromDates(userID, dates)
+	} else {
+		streak.UserID = userID
+		streak.RecordPractice(date)
+	}
+	saveEntry(s.undo, s.streaks, userID)
+	s.streaks[userID] = streak
+
+	return s.record("RecordDailyActivity", orgID, userID, characterID, date, at)
+}
+
+// GetStreak 獲取用戶的連續練習記錄
+func (s *MemoryStorage) GetStreak(ctx context.Context, orgID, userID int) (models.Streak, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return models.Streak{}, err
+	}
+
+	streak, exists := s.streaks[userID]
+	if !exists {
+		return models.Streak{UserID: userID}, nil
+	}
+	return streak, nil
+}
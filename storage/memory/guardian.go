@@ -0,0 +1,136 @@
+// backend/storage/memory/guardian.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"fmt"
+)
+
+// CreateGuardianLink 創建監護人連結，同一組帳號已有待處理或已同意的連結時返回錯誤
+func (s *MemoryStorage) CreateGuardianLink(ctx context.Context, link models.GuardianLink) (*models.GuardianLink, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.GuardianLink, error) {
+			return tx.CreateGuardianLink(ctx, link)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.guardianLinks {
+		if existing.GuardianID == link.GuardianID && existing.ChildID == link.ChildID &&
+			existing.Status != models.GuardianLinkRejected {
+			return nil, fmt.Errorf("guardian link: %w", storage.ErrConflict)
+		}
+	}
+
+	saveValue(s.undo, &s.guardianLinkSeq)
+	saveValue(s.undo, &s.guardianLinks)
+	s.guardianLinkSeq++
+	link.ID = s.guardianLinkSeq
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = s.now()
+	}
+	s.guardianLinks = append(s.guardianLinks, link)
+	if err := s.recordAt("CreateGuardianLink", link.CreatedAt, link); err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetGuardianLinkByID 根據ID獲取組織內的監護人連結
+func (s *MemoryStorage) GetGuardianLinkByID(ctx context.Context, orgID, id int) (*models.GuardianLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, link := range s.guardianLinks {
+		if link.ID == id && s.linkInOrg(orgID, link) {
+			return &link, nil
+		}
+	}
+	return nil, fmt.Errorf("guardian link with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// GetGuardianLinksByGuardianID 獲取監護人的所有連結
+func (s *MemoryStorage) GetGuardianLinksByGuardianID(ctx context.Context, orgID, guardianID int) ([]models.GuardianLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links := []models.GuardianLink{}
+	for _, link := range s.guardianLinks {
+		if link.GuardianID == guardianID && s.linkInOrg(orgID, link) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// GetGuardianLinksByChildID 獲取孩子帳號的所有連結
+func (s *MemoryStorage) GetGuardianLinksByChildID(ctx context.Context, orgID, childID int) ([]models.GuardianLink, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	links := []models.GuardianLink{}
+	for _, link := range s.guardianLinks {
+		if link.ChildID == childID && s.linkInOrg(orgID, link) {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// UpdateGuardianLink 更新監護人連結狀態
+func (s *MemoryStorage) UpdateGuardianLink(ctx context.Context, link models.GuardianLink) (*models.GuardianLink, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.GuardianLink, error) {
+			return tx.UpdateGuardianLink(ctx, link)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.guardianLinks {
+		if existing.ID == link.ID {
+			saveValue(s.undo, &s.guardianLinks[i])
+			s.guardianLinks[i] = link
+			if err := s.record("UpdateGuardianLink", link); err != nil {
+				return nil, err
+			}
+			return &link, nil
+		}
+	}
+	return nil, fmt.Errorf("guardian link with ID %d: %w", link.ID, storage.ErrNotFound)
+}
+
+// DeleteGuardianLink 刪除組織內的監護人連結
+func (s *MemoryStorage) DeleteGuardianLink(ctx context.Context, orgID, id int) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.DeleteGuardianLink(ctx, orgID, id)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, link := range s.guardianLinks {
+		if link.ID == id && s.linkInOrg(orgID, link) {
+			saveSlice(s.undo, &s.guardianLinks)
+			s.guardianLinks = append(s.guardianLinks[:i], s.guardianLinks[i+1:]...)
+			return s.record("DeleteGuardianLink", orgID, id)
+		}
+	}
+	return fmt.Errorf("guardian link with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// linkInOrg 判斷連結的監護人與孩子帳號是否都屬於組織，呼叫者需持有鎖
+func (s *MemoryStorage) linkInOrg(orgID int, link models.GuardianLink) bool {
+	if _, err := s.userByID(orgID, link.GuardianID); err != nil {
+		return false
+	}
+	_, err := s.userByID(orgID, link.ChildID)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+// backend/storage/memory/history.go
+package memory
+
+import (
+	"backend/models"
+	"context"
+	"time"
+)
+
+// historySlotSize 進度歷史彙總的時段長度
+// 各時區的日界線都落在 15 分鐘的整數倍上，以時段彙總即可依查詢的時區組成日或週
+const historySlotSize = 15 * time.Minute
+
+// historySlot 用戶在一個時段內的練習彙總
+type historySlot struct {
+	Attempts int     `json:"attempts"`
+	ScoreSum float64 `json:"scoreSum"`
+	Mastered int     `json:"mastered"` // 已熟練字元數在此時段內的變化
+}
+
+// updateHistory 更新用戶在時間所屬時段的彙總
+func (s *MemoryStorage) updateHistory(userID int, at time.Time, update func(slot *historySlot)) {
+	slots, exists := s.history[userID]
+	if !exists {
+		saveEntry(s.undo, s.history, userID)
+		slots = make(map[int64]historySlot)
+		s.history[userID] = slots
+	}
+
+	key := at.Truncate(historySlotSize).Unix()
+	saveEntry(s.undo, slots, key)
+	slot := slots[key]
+	update(&slot)
+	slots[key] = slot
+}
+
+// updateMasteredHistory 依練習前後的進度記錄已熟練字元數的變化
+// 熟練的字元若不再練習，會在可預期的時間衰減至門檻以下，因此預先記下該時間的減少，再次練習時撤銷
+func (s *MemoryStorage) updateMasteredHistory(userID int, before, after models.CharacterProgress, at time.Time) {
+	change := func(t time.Time, delta int) {
+		s.updateHistory(userID, t, func(slot *historySlot) {
+			slot.Mastered += delta
+		})
+	}
+
+	mastered := false
+	if until, ok := before.MasteredUntil(); ok && !until.Before(at) {
+		change(until, 1)
+		mastered = true
+	}
+
+	if until, ok := after.MasteredUntil(); ok {
+		if !mastered {
+			change(at, 1)
+		}
+		change(until, -1)
+	} else if mastered {
+		change(at, -1)
+	}
+}
+
+// GetProgressHistory 依日或週彙總用戶的練習次數、平均得分與已熟練字元數
+func (s *MemoryStorage) GetProgressHistory(ctx context.Context, orgID int, query models.ProgressHistoryQuery) ([]models.ProgressHistoryPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, query.UserID); err != nil {
+		return nil, err
+	}
+
+	history := []models.ProgressHistoryPoint{}
+	index := make(map[int64]int) // 區間起點 -> 區間在結果中的位置
+	start := periodStart(query.From, query.Interval)
+	for ; start.Before(query.To); start = nextPeriod(start, query.Interval) {
+		index[start.Unix()] = len(history)
+		history = append(history, models.ProgressHistoryPoint{PeriodStart: start})
+	}
+	if len(history) == 0 {
+		return history, nil
+	}
+	first, end := history[0].PeriodStart, start
+
+	// 將時段彙總歸入所屬區間，第一個區間之前的變化累計為起始的已熟練字元數
+	mastered := 0
+	scoreSums := make([]float64, len(history))
+	changes := make([]int, len(history))
+	for key, slot := range s.history[query.UserID] {
+		at := time.Unix(key, 0).In(query.From.Location())
+		if at.Before(first) {
+			mastered += slot.Mastered
+			continue
+		}
+		if !at.Before(end) {
+			continue
+		}
+
+		i := index[periodStart(at, query.Interval).Unix()]
+		history[i].Attempts += slot.Attempts
+		scoreSums[i] += slot.ScoreSum
+		changes[i] += slot.Mastered
+	}
+
+	for i := range history {
+		if history[i].Attempts > 0 {
+			history[i].AvgScore = scoreSums[i] / float64(history[i].Attempts)
+		}
+		mastered += changes[i]
+		history[i].MasteredCharacters = mastered
+	}
+
+	return history, nil
+}
+
+// periodStart 取得時間所在區間的起點（週以星期一為起點）
+func periodStart(t time.Time, interval models.HistoryInterval) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == models.HistoryIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// nextPeriod 取得下一個區間的起點
+func nextPeriod(start time.Time, interval models.HistoryInterval) time.Time {
+	if interval == models.HistoryIntervalWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,87 @@
+// backend/storage/memory/leaderboard.go
+package memory
+
+import (
+	"backend/models"
+	"context"
+	"sort"
+	"time"
+)
+
+// updateAggregates 更新用戶在時間所屬各期間的練習彙總
+func (s *MemoryStorage) updateAggregates(userID int, at time.Time, update func(aggregate *models.PracticeAggregate)) {
+	for _, period := range models.Periods {
+		key := models.PeriodKey(period, at)
+		users, exists := s.aggregates[key]
+		if !exists {
+			saveEntry(s.undo, s.aggregates, key)
+			users = make(map[int]models.PracticeAggregate)
+			s.aggregates[key] = users
+		}
+
+		saveEntry(s.undo, users, userID)
+		aggregate := users[userID]
+		update(&aggregate)
+		users[userID] = aggregate
+	}
+}
+
+// GetLeaderboard 依期間彙總產生組織內的排行榜，排除選擇不公開的用戶
+func (s *MemoryStorage) GetLeaderboard(ctx context.Context, query models.LeaderboardQuery) ([]models.LeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var scope map[int]bool
+	if query.UserIDs != nil {
+		scope = make(map[int]bool, len(query.UserIDs))
+		for _, id := range query.UserIDs {
+			scope[id] = true
+		}
+	}
+
+	usernames := make(map[int]string)
+	for _, user := range s.users {
+		if user.OrgID == query.OrgID && !user.LeaderboardOptOut {
+			usernames[user.ID] = user.Username
+		}
+	}
+
+	entries := []models.LeaderboardEntry{}
+	for userID, aggregate := range s.aggregates[models.PeriodKey(query.Period, query.At)] {
+		username, visible := usernames[userID]
+		if !visible || (scope != nil && !scope[userID]) {
+			continue
+		}
+
+		value, ok := aggregate.Value(query.Metric)
+		if !ok {
+			continue
+		}
+		entries = append(entries, models.LeaderboardEntry{
+			UserID:   userID,
+			Username: username,
+			Value:    value,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+
+	// 同分同名次
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Value == entries[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,12 @@
+// backend/storage/memory/lock_other.go
+
+//go:build !unix
+
+package memory
+
+import "os"
+
+// lockDir 不支援 flock 的平台只建立鎖定檔，無法偵測其他程序
+func lockDir(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+}
@@ -0,0 +1,72 @@
+// backend/storage/memory/organization.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"fmt"
+)
+
+// GetOrganizations 獲取所有組織
+func (s *MemoryStorage) GetOrganizations(ctx context.Context) ([]models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.Organization{}, s.organizations...), nil
+}
+
+// CreateOrganization 創建組織，代稱不可重複
+func (s *MemoryStorage) CreateOrganization(ctx context.Context, org models.Organization) (*models.Organization, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Organization, error) {
+			return tx.CreateOrganization(ctx, org)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.organizations {
+		if existing.Slug == org.Slug {
+			return nil, fmt.Errorf("organization slug %q: %w", org.Slug, storage.ErrConflict)
+		}
+	}
+
+	saveValue(s.undo, &s.organizations)
+	org.ID = len(s.organizations) + 1
+	if org.CreatedAt.IsZero() {
+		org.CreatedAt = s.now()
+	}
+	s.organizations = append(s.organizations, org)
+	if err := s.recordAt("CreateOrganization", org.CreatedAt, org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// GetOrganizationByID 根據ID獲取組織
+func (s *MemoryStorage) GetOrganizationByID(ctx context.Context, id int) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, org := range s.organizations {
+		if org.ID == id {
+			return &org, nil
+		}
+	}
+	return nil, fmt.Errorf("organization with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// GetOrganizationBySlug 根據代稱獲取組織
+func (s *MemoryStorage) GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, org := range s.organizations {
+		if org.Slug == slug {
+			return &org, nil
+		}
+	}
+	return nil, fmt.Errorf("organization %q: %w", slug, storage.ErrNotFound)
+}
@@ -0,0 +1,571 @@
+// backend/storage/memory/persist.go
+package memory
+
+import (
+	"backend/models"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 持久化目錄中的檔案名稱
+const (
+	snapshotFile = "snapshot.json"
+	journalFile  = "journal.jsonl"
+	lockFile     = "LOCK"
+)
+
+// ErrLocked 持久化目錄已由其他程序開啟，例如伺服器執行中時又以同一個 DATA_DIR 執行命令列工具
+var ErrLocked = errors.New("data directory is in use by another process")
+
+// persistence 快照與只附加日誌：快照保存完整狀態，日誌逐行記錄快照後提交的寫入操作
+type persistence struct {
+	dir     string
+	lock    *os.File // 持有期間其他程序無法開啟同一目錄
+	journal *os.File
+	size    int64 // 日誌中已完整寫入的位元組數
+	seq     int64 // 最後寫入日誌的序號
+
+	snapshotMu sync.Mutex // 快照只持有讀鎖，避免兩次快照同時改寫檔案
+}
+
+// journalEntry 日誌中的一行，同一交易的操作寫在同一行，確保一併重播
+type journalEntry struct {
+	Seq int64       `json:"seq"`
+	Ops []journalOp `json:"ops"`
+}
+
+// journalOp 一次成功的寫入操作與其參數
+type journalOp struct {
+	Op   string            `json:"op"`
+	At   time.Time         `json:"at"` // 操作使用的時間，重播時作為時鐘，零值表示不依賴時間
+	Args []json.RawMessage `json:"args"`
+}
+
+// storedUser 持久化用的用戶，保留 JSON 回應中隱藏的密碼
+type storedUser struct {
+	models.User
+	Password string `json:"password"`
+}
+
+func newStoredUser(user models.User) storedUser {
+	return storedUser{User: user, Password: user.Password}
+}
+
+func (u storedUser) user() models.User {
+	user := u.User
+	user.Password = u.Password
+	return user
+}
+
+// snapshot 快照檔的內容
+type snapshot struct {
+	Seq              int64                                       `json:"seq"`
+	Organizations    []models.Organization                       `json:"organizations"`
+	Users            []storedUser                                `json:"users"`
+	Characters       []models.CharacterPreview                   `json:"characters"`
+	CharacterDetails map[int]models.Character                    `json:"characterDetails"`
+	Decks            []models.Deck                               `json:"decks"`
+	StrokeRecords    map[int][]models.StrokeRecord               `json:"strokeRecords"`
+	ClientRecordIDs  map[int]map[string]int                      `json:"clientRecordIds"`
+	StrokeStats      []models.StrokeStat                         `json:"strokeStats"`
+	UserProgress     map[int]models.UserProgress                 `json:"userProgress"`
+	RecordCounter    int                                         `json:"recordCounter"`
+	DailyGoals       map[int]models.DailyGoal                    `json:"dailyGoals"`
+	DailyActivity    map[int]map[string]models.DailyActivity     `json:"dailyActivity"`
+	Streaks          map[int]models.Streak                       `json:"streaks"`
+	Achievements     map[int][]models.UserAchievement            `json:"achievements"`
+	Aggregates       map[string]map[int]models.PracticeAggregate `json:"aggregates"`
+	History          map[int]map[int64]historySlot               `json:"history"`
+	Classrooms       []models.Classroom                          `json:"classrooms"`
+	ClassMembers     map[int][]models.ClassMembership            `json:"classMembers"`
+	Assignments      []models.Assignment                         `json:"assignments"`
+	AssignmentSeq    int                                         `json:"assignmentSeq"`
+	DueProgress      map[int]map[int]models.UserProgress         `json:"dueProgress"`
+	GuardianLinks    []models.GuardianLink                       `json:"guardianLinks"`
+	GuardianLinkSeq  int                                         `json:"guardianLinkSeq"`
+	AuditEntries     []models.AuditEntry                         `json:"auditEntries"`
+}
+
+// Open 從目錄中的快照與日誌還原記憶體儲存，之後提交的寫入都會附加至日誌
+// 目錄中沒有快照時以預設資料建立第一份快照；日誌結尾未寫完的一行視為未提交並捨棄。
+// 開啟期間持有目錄的獨佔鎖，目錄已由其他程序開啟時返回 ErrLocked。
+func Open(dir string) (*MemoryStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	lock, err := lockDir(filepath.Join(dir, lockFile))
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := open(dir, lock)
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// open 在取得目錄鎖之後還原快照與日誌
+func open(dir string, lock *os.File) (*MemoryStorage, error) {
+	s := NewMemoryStorage()
+	p := &persistence{dir: dir, lock: lock}
+
+	snap, err := readSnapshot(filepath.Join(dir, snapshotFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		s.restore(snap)
+		p.seq = snap.Seq
+	}
+
+	journal, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	p.journal = journal
+
+	if err := s.replay(p); err != nil {
+		journal.Close()
+		return nil, err
+	}
+
+	s.persist = p
+	if snap == nil {
+		if err := s.Snapshot(); err != nil {
+			journal.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Snapshot 將目前的完整狀態寫入快照檔並清空日誌，未啟用持久化時不做任何事
+// 編碼與寫入磁碟期間只持有讀鎖，讀取請求不會被阻擋，寫入則等到快照完成後才附加至新的日誌。
+func (s *MemoryStorage) Snapshot() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.persist == nil {
+		return nil
+	}
+	s.persist.snapshotMu.Lock()
+	defer s.persist.snapshotMu.Unlock()
+	return s.persist.writeSnapshot(s.snapshot())
+}
+
+// Persistent 判斷儲存是否由 Open 開啟並將寫入保存至磁碟
+func (s *MemoryStorage) Persistent() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.persist != nil
+}
+
+// Close 關閉日誌檔案並釋放目錄鎖
+func (s *MemoryStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.persist == nil {
+		return nil
+	}
+	err := s.persist.journal.Close()
+	if lockErr := s.persist.lock.Close(); err == nil {
+		err = lockErr
+	}
+	s.persist = nil
+	return err
+}
+
+// record 記錄一次成功的寫入操作，暫存至交易提交時附加至日誌
+// 交易外的寫入由 autoCommit 包裝成單次寫入的交易，日誌寫入失敗時記憶體中的變更一併回滾。
+func (s *MemoryStorage) record(op string, args ...interface{}) error {
+	return s.recordAt(op, time.Time{}, args...)
+}
+
+// recordAt 記錄一次依賴當前時間的寫入操作，重播時以 at 作為時鐘
+func (s *MemoryStorage) recordAt(op string, at time.Time, args ...interface{}) error {
+	if s.persist == nil {
+		return nil
+	}
+
+	entry := journalOp{Op: op, At: at, Args: make([]json.RawMessage, len(args))}
+	for i, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return fmt.Errorf("journal %s: %w", op, err)
+		}
+		entry.Args[i] = data
+	}
+
+	s.undo.ops = append(s.undo.ops, entry)
+	return nil
+}
+
+// append 將已提交的操作以一行寫入日誌並同步至磁碟
+func (p *persistence) append(ops []journalOp) error {
+	line, err := json.Marshal(journalEntry{Seq: p.seq + 1, Ops: ops})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := p.journal.Write(line); err != nil {
+		// 移除寫到一半的內容，避免與下一行接在一起
+		p.journal.Truncate(p.size)
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := p.journal.Sync(); err != nil {
+		p.journal.Truncate(p.size)
+		return fmt.Errorf("sync journal: %w", err)
+	}
+	p.size += int64(len(line))
+	p.seq++
+	return nil
+}
+
+// writeSnapshot 先寫入暫存檔再改名取代快照，完成後清空日誌
+// 改名後、清空前中斷時，重播會依序號略過快照已包含的日誌行
+func (p *persistence) writeSnapshot(snap *snapshot) error {
+	snap.Seq = p.seq
+
+	tmp, err := os.CreateTemp(p.dir, snapshotFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	if err := json.NewEncoder(writer).Encode(snap); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(p.dir, snapshotFile)); err != nil {
+		return err
+	}
+	if err := p.journal.Truncate(0); err != nil {
+		return err
+	}
+	p.size = 0
+	return nil
+}
+
+// readSnapshot 讀取快照檔
+func readSnapshot(path string) (*snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snap snapshot
+	if err := json.NewDecoder(bufio.NewReader(file)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("read snapshot %s: %w", path, err)
+	}
+	return &snap, nil
+}
+
+// replay 依序重播日誌中快照之後的操作，並截斷結尾未寫完的一行
+func (s *MemoryStorage) replay(p *persistence) error {
+	if _, err := p.journal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(p.journal)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break // 沒有換行的最後一行尚未完整寫入
+		}
+		if err != nil {
+			return err
+		}
+
+		var entry journalEntry
+		if err := json.Unmarshal(bytes.TrimSpace(line), &entry); err != nil {
+			break
+		}
+		if entry.Seq > p.seq {
+			for _, op := range entry.Ops {
+				if err := s.apply(op); err != nil {
+					return fmt.Errorf("replay journal entry %d: %s: %w", entry.Seq, op.Op, err)
+				}
+			}
+			p.seq = entry.Seq
+		}
+		offset += int64(len(line))
+	}
+
+	p.size = offset
+	return p.journal.Truncate(offset)
+}
+
+// apply 以記錄的時間重新執行一次寫入操作
+func (s *MemoryStorage) apply(op journalOp) error {
+	if !op.At.IsZero() {
+		s.now = func() time.Time { return op.At }
+		defer func() { s.now = time.Now }()
+	}
+
+	ctx := context.Background()
+	var err error
+	switch op.Op {
+	case "CreateOrganization":
+		var org models.Organization
+		if err = decodeArgs(op.Args, &org); err == nil {
+			_, err = s.CreateOrganization(ctx, org)
+		}
+	case "CreateUser":
+		var user storedUser
+		if err = decodeArgs(op.Args, &user); err == nil {
+			_, err = s.CreateUser(ctx, user.user())
+		}
+	case "UpdateUser":
+		var user storedUser
+		if err = decodeArgs(op.Args, &user); err == nil {
+			_, err = s.UpdateUser(ctx, user.user())
+		}
+	case "PurgeUser":
+		var orgID, userID int
+		var at time.Time
+		if err = decodeArgs(op.Args, &orgID, &userID, &at); err == nil {
+			err = s.PurgeUser(ctx, orgID, userID, at)
+		}
+	case "CreateCharacter":
+		var character models.Character
+		if err = decodeArgs(op.Args, &character); err == nil {
+			_, err = s.CreateCharacter(ctx, character)
+		}
+	case "UpdateCharacter":
+		var character models.Character
+		if err = decodeArgs(op.Args, &character); err == nil {
+			_, err = s.UpdateCharacter(ctx, character)
+		}
+	case "CreateDeck":
+		var deck models.Deck
+		if err = decodeArgs(op.Args, &deck); err == nil {
+			_, err = s.CreateDeck(ctx, deck)
+		}
+	case "CreateStrokeRecord":
+		var record models.StrokeRecord
+		if err = decodeArgs(op.Args, &record); err == nil {
+			_, err = s.CreateStrokeRecord(ctx, record)
+		}
+	case "UpdateUserProgress":
+		var orgID, userID, characterID, strokeIndex int
+		var score float64
+		var at time.Time
+		if err = decodeArgs(op.Args, &orgID, &userID, &characterID, &strokeIndex, &score, &at); err == nil {
+			err = s.UpdateUserProgress(ctx, orgID, userID, characterID, strokeIndex, score, at)
+		}
+	case "SetDailyGoal":
+		var goal models.DailyGoal
+		if err = decodeArgs(op.Args, &goal); err == nil {
+			err = s.SetDailyGoal(ctx, goal)
+		}
+	case "RecordDailyActivity":
+		var orgID, userID, characterID int
+		var date string
+		var at time.Time
+		if err = decodeArgs(op.Args, &orgID, &userID, &characterID, &date, &at); err == nil {
+			err = s.RecordDailyActivity(ctx, orgID, userID, characterID, date, at)
+		}
+	case "UnlockAchievement":
+		var orgID, userID int
+		var achievementID string
+		var at time.Time
+		if err = decodeArgs(op.Args, &orgID, &userID, &achievementID, &at); err == nil {
+			_, err = s.UnlockAchievement(ctx, orgID, userID, achievementID, at)
+		}
+	case "CreateClassroom":
+		var classroom models.Classroom
+		if err = decodeArgs(op.Args, &classroom); err == nil {
+			_, err = s.CreateClassroom(ctx, classroom)
+		}
+	case "AddClassMember":
+		var classID, userID int
+		if err = decodeArgs(op.Args, &classID, &userID); err == nil {
+			_, err = s.AddClassMember(ctx, classID, userID)
+		}
+	case "CreateAssignment":
+		var assignment models.Assignment
+		if err = decodeArgs(op.Args, &assignment); err == nil {
+			_, err = s.CreateAssignment(ctx, assignment)
+		}
+	case "UpdateAssignment":
+		var assignment models.Assignment
+		if err = decodeArgs(op.Args, &assignment); err == nil {
+			_, err = s.UpdateAssignment(ctx, assignment)
+		}
+	case "DeleteAssignment":
+		var orgID, id int
+		if err = decodeArgs(op.Args, &orgID, &id); err == nil {
+			err = s.DeleteAssignment(ctx, orgID, id)
+		}
+	case "SaveAssignmentDueProgress":
+		var assignmentID, userID int
+		var progress models.UserProgress
+		if err = decodeArgs(op.Args, &assignmentID, &userID, &progress); err == nil {
+			_, err = s.SaveAssignmentDueProgress(ctx, assignmentID, userID, progress)
+		}
+	case "CreateGuardianLink":
+		var link models.GuardianLink
+		if err = decodeArgs(op.Args, &link); err == nil {
+			_, err = s.CreateGuardianLink(ctx, link)
+		}
+	case "UpdateGuardianLink":
+		var link models.GuardianLink
+		if err = decodeArgs(op.Args, &link); err == nil {
+			_, err = s.UpdateGuardianLink(ctx, link)
+		}
+	case "DeleteGuardianLink":
+		var orgID, id int
+		if err = decodeArgs(op.Args, &orgID, &id); err == nil {
+			err = s.DeleteGuardianLink(ctx, orgID, id)
+		}
+	case "CreateAuditEntry":
+		var entry models.AuditEntry
+		if err = decodeArgs(op.Args, &entry); err == nil {
+			_, err = s.CreateAuditEntry(ctx, entry)
+		}
+	default:
+		err = errors.New("unknown operation")
+	}
+	return err
+}
+
+// decodeArgs 依序解碼操作參數
+func decodeArgs(args []json.RawMessage, targets ...interface{}) error {
+	if len(args) != len(targets) {
+		return fmt.Errorf("got %d arguments, want %d", len(args), len(targets))
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(args[i], target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot 複製目前的完整狀態，呼叫者需持有鎖
+func (s *MemoryStorage) snapshot() *snapshot {
+	users := make([]storedUser, len(s.users))
+	for i, user := range s.users {
+		users[i] = newStoredUser(user)
+	}
+	stats := []models.StrokeStat{}
+	for _, userStats := range s.strokeStats {
+		for _, stat := range userStats {
+			stats = append(stats, stat)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		if a.CharacterID != b.CharacterID {
+			return a.CharacterID < b.CharacterID
+		}
+		return a.StrokeIndex < b.StrokeIndex
+	})
+
+	return &snapshot{
+		Organizations:    s.organizations,
+		Users:            users,
+		Characters:       s.characters,
+		CharacterDetails: s.characterDetails,
+		Decks:            s.decks,
+		StrokeRecords:    s.strokeRecords,
+		ClientRecordIDs:  s.clientRecordIDs,
+		StrokeStats:      stats,
+		UserProgress:     s.userProgress,
+		RecordCounter:    s.recordCounter,
+		DailyGoals:       s.dailyGoals,
+		DailyActivity:    s.dailyActivity,
+		Streaks:          s.streaks,
+		Achievements:     s.achievements,
+		Aggregates:       s.aggregates,
+		History:          s.history,
+		Classrooms:       s.classrooms,
+		ClassMembers:     s.classMembers,
+		Assignments:      s.assignments,
+		AssignmentSeq:    s.assignmentSeq,
+		DueProgress:      s.dueProgress,
+		GuardianLinks:    s.guardianLinks,
+		GuardianLinkSeq:  s.guardianLinkSeq,
+		AuditEntries:     s.auditEntries,
+	}
+}
+
+// restore 以快照內容取代目前的狀態，快照中缺少的欄位保留空值
+func (s *MemoryStorage) restore(snap *snapshot) {
+	users := make([]models.User, len(snap.Users))
+	for i, user := range snap.Users {
+		users[i] = user.user()
+	}
+	stats := make(map[int]map[strokeKey]models.StrokeStat)
+	for _, stat := range snap.StrokeStats {
+		if stats[stat.UserID] == nil {
+			stats[stat.UserID] = make(map[strokeKey]models.StrokeStat)
+		}
+		stats[stat.UserID][strokeKey{stat.CharacterID, stat.StrokeIndex}] = stat
+	}
+
+	s.organizations = snap.Organizations
+	s.users = users
+	s.characters = snap.Characters
+	s.characterDetails = orEmpty(snap.CharacterDetails)
+	s.decks = snap.Decks
+	s.strokeRecords = orEmpty(snap.StrokeRecords)
+	s.clientRecordIDs = orEmpty(snap.ClientRecordIDs)
+	s.strokeStats = stats
+	s.userProgress = orEmpty(snap.UserProgress)
+	s.recordCounter = snap.RecordCounter
+	s.dailyGoals = orEmpty(snap.DailyGoals)
+	s.dailyActivity = orEmpty(snap.DailyActivity)
+	s.streaks = orEmpty(snap.Streaks)
+	s.achievements = orEmpty(snap.Achievements)
+	s.aggregates = orEmpty(snap.Aggregates)
+	s.history = orEmpty(snap.History)
+	s.classrooms = snap.Classrooms
+	s.classMembers = orEmpty(snap.ClassMembers)
+	s.assignments = snap.Assignments
+	s.assignmentSeq = snap.AssignmentSeq
+	s.dueProgress = orEmpty(snap.DueProgress)
+	s.guardianLinks = snap.GuardianLinks
+	s.guardianLinkSeq = snap.GuardianLinkSeq
+	s.auditEntries = snap.AuditEntries
+}
+
+// orEmpty 以空映射取代 nil 映射，避免寫入時 panic
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
@@ -0,0 +1,254 @@
+// backend/storage/memory/persist_test.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/storage/storagetest"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mustOpen 開啟持久化的記憶體儲存
+func mustOpen(t *testing.T, dir string) *MemoryStorage {
+	t.Helper()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// populate 寫入涵蓋各類資料的測試內容，返回建立的用戶
+func populate(t *testing.T, s *MemoryStorage) *models.User {
+	t.Helper()
+	ctx := context.Background()
+
+	user, err := s.CreateUser(ctx, models.User{Username: "erin", Password: "secret", Role: models.RoleStudent, OrgID: models.DefaultOrganizationID})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	err = s.RunInTx(ctx, func(tx storage.Storage) error {
+		record, err := tx.CreateStrokeRecord(ctx, models.StrokeRecord{
+			UserID:      user.ID,
+			CharacterID: 1,
+			Path:        []models.Node{{X: 0, Y: 0}, {X: 10, Y: 0}},
+			Score:       0.95,
+			ClientID:    "offline-1",
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.UpdateUserProgress(ctx, user.OrgID, user.ID, 1, 0, record.Score, record.CreatedAt); err != nil {
+			return err
+		}
+		if err := tx.RecordDailyActivity(ctx, user.OrgID, user.ID, 1, record.CreatedAt.Format("2006-01-02"), record.CreatedAt); err != nil {
+			return err
+		}
+		_, err = tx.UnlockAchievement(ctx, user.OrgID, user.ID, "first_stroke", record.CreatedAt)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("RunInTx: %v", err)
+	}
+
+	classroom, err := s.CreateClassroom(ctx, models.Classroom{Name: "1A", TeacherID: 1, OrgID: models.DefaultOrganizationID, EnrolmentCode: "ABC123"})
+	if err != nil {
+		t.Fatalf("CreateClassroom: %v", err)
+	}
+	if _, err := s.AddClassMember(ctx, classroom.ID, user.ID); err != nil {
+		t.Fatalf("AddClassMember: %v", err)
+	}
+	assignment, err := s.CreateAssignment(ctx, models.Assignment{ClassID: classroom.ID, Title: "一", CharacterIDs: []int{1}, DueAt: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateAssignment: %v", err)
+	}
+	progress, err := s.GetUserProgress(ctx, user.OrgID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	if _, err := s.SaveAssignmentDueProgress(ctx, assignment.ID, user.ID, progress); err != nil {
+		t.Fatalf("SaveAssignmentDueProgress: %v", err)
+	}
+	return user
+}
+
+// assertSameState 以快照的 JSON 內容比較兩個儲存的完整狀態
+func assertSameState(t *testing.T, want, got *MemoryStorage, userID int) {
+	t.Helper()
+	want.mu.RLock()
+	defer want.mu.RUnlock()
+	got.mu.RLock()
+	defer got.mu.RUnlock()
+
+	wantJSON, err := json.Marshal(want.snapshot())
+	if err != nil {
+		t.Fatalf("marshal snapshot: %v", err)
+	}
+	gotJSON, err := json.Marshal(got.snapshot())
+	if err != nil {
+		t.Fatalf("marshal snapshot: %v", err)
+	}
+	if !bytes.Equal(wantJSON, gotJSON) {
+		t.Fatalf("restored state differs:\nwant %s\ngot  %s", wantJSON, gotJSON)
+	}
+	if got.users[userID-1].Password != "secret" {
+		t.Fatal("password not restored")
+	}
+}
+
+func TestOpenReplaysJournal(t *testing.T) {
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+	user := populate(t, s)
+	s.Close()
+
+	assertSameState(t, s, mustOpen(t, dir), user.ID)
+}
+
+func TestOpenRestoresSnapshotAndJournal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+	user := populate(t, s)
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := s.SetDailyGoal(ctx, models.DailyGoal{UserID: user.ID, TargetCharacters: 20, TargetMinutes: 15, Timezone: "Asia/Taipei"}); err != nil {
+		t.Fatalf("SetDailyGoal: %v", err)
+	}
+	s.Close()
+
+	restored := mustOpen(t, dir)
+	assertSameState(t, s, restored, user.ID)
+	if goal, _ := restored.GetDailyGoal(ctx, user.OrgID, user.ID); goal.TargetCharacters != 20 {
+		t.Fatalf("goal after restore = %+v, want target 20", goal)
+	}
+}
+
+func TestOpenSkipsJournalEntriesInSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+	user := populate(t, s)
+
+	// 模擬快照改名後、清空日誌前中斷
+	journal, err := os.ReadFile(filepath.Join(dir, journalFile))
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	if err := s.Snapshot(); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	s.Close()
+	if err := os.WriteFile(filepath.Join(dir, journalFile), journal, 0o644); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+
+	assertSameState(t, s, mustOpen(t, dir), user.ID)
+}
+
+func TestOpenDiscardsPartialJournalLine(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+	user := populate(t, s)
+	s.Close()
+
+	path := filepath.Join(dir, journalFile)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	file.WriteString(`{"seq":99,"ops":[{"op":"CreateUs`)
+	file.Close()
+
+	restored := mustOpen(t, dir)
+	assertSameState(t, s, restored, user.ID)
+
+	// 截斷後的日誌可繼續附加並再次還原
+	if _, err := restored.CreateUser(ctx, models.User{Username: "frank", Password: "pw", OrgID: models.DefaultOrganizationID}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	restored.Close()
+	if _, err := mustOpen(t, dir).GetUserByUsername(ctx, models.DefaultOrganizationID, "frank"); err != nil {
+		t.Fatalf("user written after truncation not restored: %v", err)
+	}
+}
+
+func TestRolledBackTransactionIsNotJournaled(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+
+	failure := errors.New("fail")
+	err := s.RunInTx(ctx, func(tx storage.Storage) error {
+		if _, err := tx.CreateUser(ctx, models.User{Username: "ghost", Password: "pw", OrgID: models.DefaultOrganizationID}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("RunInTx = %v, want failure", err)
+	}
+	s.Close()
+
+	if _, err := mustOpen(t, dir).GetUserByUsername(ctx, models.DefaultOrganizationID, "ghost"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("rolled back user after restore = %v, want ErrNotFound", err)
+	}
+}
+
+func TestJournalFailureRollsBackWrite(t *testing.T) {
+	ctx := context.Background()
+	s := mustOpen(t, t.TempDir())
+
+	// 關閉日誌檔案使下一次附加失敗
+	s.persist.journal.Close()
+	if _, err := s.CreateUser(ctx, models.User{Username: "lost", Password: "pw", OrgID: models.DefaultOrganizationID}); err == nil {
+		t.Fatal("CreateUser succeeded although the journal could not be written")
+	}
+	if _, err := s.GetUserByUsername(ctx, models.DefaultOrganizationID, "lost"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("user after a failed journal write = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOpenRejectsDirectoryInUse(t *testing.T) {
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+
+	if _, err := Open(dir); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second Open = %v, want ErrLocked", err)
+	}
+	s.Close()
+	mustOpen(t, dir)
+}
+
+func TestReplayUsesRecordedTime(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := mustOpen(t, dir)
+	org, err := s.CreateOrganization(ctx, models.Organization{Name: "School", Slug: "school"})
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	s.Close()
+
+	time.Sleep(time.Millisecond)
+	restored, err := mustOpen(t, dir).GetOrganizationBySlug(ctx, "school")
+	if err != nil {
+		t.Fatalf("GetOrganizationBySlug: %v", err)
+	}
+	if !restored.CreatedAt.Equal(org.CreatedAt) {
+		t.Fatalf("CreatedAt = %v, want %v", restored.CreatedAt, org.CreatedAt)
+	}
+}
+
+func TestPersistentConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Storage { return mustOpen(t, t.TempDir()) })
+}
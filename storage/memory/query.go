@@ -0,0 +1,62 @@
+// backend/storage/memory/query.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"sort"
+)
+
+// QueryStrokeRecords 依條件分頁查詢用戶的筆畫記錄，按記錄ID由舊到新排序
+func (s *MemoryStorage) QueryStrokeRecords(ctx context.Context, orgID int, query models.StrokeRecordQuery) (*models.StrokeRecordPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, query.UserID); err != nil {
+		return nil, err
+	}
+
+	afterID, err := storage.DecodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// 記錄依ID遞增儲存，直接定位到游標之後
+	records := s.strokeRecords[query.UserID]
+	start := sort.Search(len(records), func(i int) bool {
+		return records[i].ID > afterID
+	})
+
+	page := &models.StrokeRecordPage{Records: []models.StrokeRecord{}}
+	for i := start; i < len(records); i++ {
+		if !matchesQuery(records[i], query) {
+			continue
+		}
+		if query.Limit > 0 && len(page.Records) == query.Limit {
+			page.NextCursor = storage.EncodeCursor(page.Records[len(page.Records)-1].ID)
+			break
+		}
+		page.Records = append(page.Records, records[i])
+	}
+	return page, nil
+}
+
+// matchesQuery 檢查記錄是否符合篩選條件
+func matchesQuery(record models.StrokeRecord, query models.StrokeRecordQuery) bool {
+	switch {
+	case query.CharacterID != nil && record.CharacterID != *query.CharacterID:
+		return false
+	case query.StrokeIndex != nil && record.StrokeIndex != *query.StrokeIndex:
+		return false
+	case !query.From.IsZero() && record.CreatedAt.Before(query.From):
+		return false
+	case !query.To.IsZero() && !record.CreatedAt.Before(query.To):
+		return false
+	case query.MinScore != nil && record.Score < *query.MinScore:
+		return false
+	case query.MaxScore != nil && record.Score > *query.MaxScore:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+// backend/storage/memory/stats.go
+package memory
+
+import (
+	"backend/models"
+	"context"
+)
+
+// strokeKey 字元中的單一筆畫
+type strokeKey struct {
+	characterID int
+	strokeIndex int
+}
+
+// updateStrokeStats 將筆畫記錄併入用戶的筆畫彙總
+func (s *MemoryStorage) updateStrokeStats(record models.StrokeRecord) {
+	stats, exists := s.strokeStats[record.UserID]
+	if !exists {
+		saveEntry(s.undo, s.strokeStats, record.UserID)
+		stats = make(map[strokeKey]models.StrokeStat)
+		s.strokeStats[record.UserID] = stats
+	}
+
+	key := strokeKey{record.CharacterID, record.StrokeIndex}
+	stat, exists := stats[key]
+	if !exists {
+		stat = models.StrokeStat{
+			UserID:      record.UserID,
+			CharacterID: record.CharacterID,
+			StrokeIndex: record.StrokeIndex,
+		}
+	}
+
+	stat.Attempts++
+	stat.ScoreSum += record.Score
+	if record.Score > stat.BestScore {
+		stat.BestScore = record.Score
+	}
+	if record.Score < models.WeakStrokeScore {
+		stat.Failures++
+	}
+	if record.CreatedAt.After(stat.LastPracticedAt) {
+		stat.LastPracticedAt = record.CreatedAt
+	}
+	saveEntry(s.undo, stats, key)
+	stats[key] = stat
+}
+
+// GetStrokeStats 獲取組織內多位用戶每一筆畫的練習彙總，任一用戶不屬於該組織時返回 ErrNotFound
+func (s *MemoryStorage) GetStrokeStats(ctx context.Context, orgID int, userIDs []int) ([]models.StrokeStat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := []models.StrokeStat{}
+	for _, userID := range userIDs {
+		if _, err := s.userByID(orgID, userID); err != nil {
+			return nil, err
+		}
+		for _, stat := range s.strokeStats[userID] {
+			stats = append(stats, stat)
+		}
+	}
+	return stats, nil
+}
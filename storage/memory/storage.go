@@ -3,26 +3,57 @@ package memory
 
 import (
 	"backend/models"
-	"errors"
+	"backend/storage"
+	"context"
 	"fmt"
+	"sync"
 	"time"
 )
 
 // MemoryStorage 實現 Storage 接口的記憶體儲存
+// 所有方法皆以讀寫鎖保護，可供多個請求同時使用
 type MemoryStorage struct {
+	mu   rwLocker // 保護 data 的所有欄位
+	undo *undoLog // 交易中的還原記錄，交易外為 nil
+	*data
+}
+
+// data 記憶體儲存的所有資料，交易與外層儲存共用同一份資料
+type data struct {
+	organizations    []models.Organization
 	users            []models.User
 	characters       []models.CharacterPreview
 	characterDetails map[int]models.Character
-	strokeRecords    []models.StrokeRecord
-	userProgress     map[int]models.UserProgress // userID -> characterID -> progress
-	recordCounter    int                         // 用於生成唯一ID
+	decks            []models.Deck
+	strokeRecords    map[int][]models.StrokeRecord           // userID -> 依ID排序的記錄
+	clientRecordIDs  map[int]map[string]int                  // userID -> clientID -> recordID
+	strokeStats      map[int]map[strokeKey]models.StrokeStat // userID -> 筆畫 -> 彙總
+	userProgress     map[int]models.UserProgress             // userID -> characterID -> progress
+	recordCounter    int                                     // 用於生成唯一ID
+	dailyGoals       map[int]models.DailyGoal
+	dailyActivity    map[int]map[string]models.DailyActivity // userID -> date -> activity
+	streaks          map[int]models.Streak
+	achievements     map[int][]models.UserAchievement
+	aggregates       map[string]map[int]models.PracticeAggregate // periodKey -> userID -> aggregate
+	history          map[int]map[int64]historySlot               // userID -> 時段起點（Unix 秒）-> 彙總
+	classrooms       []models.Classroom
+	classMembers     map[int][]models.ClassMembership // classID -> members
+	assignments      []models.Assignment
+	assignmentSeq    int                                 // 用於生成作業ID，刪除後不重複使用
+	dueProgress      map[int]map[int]models.UserProgress // assignmentID -> userID -> 到期時的進度
+	guardianLinks    []models.GuardianLink
+	guardianLinkSeq  int
+	auditEntries     []models.AuditEntry
+
+	now     func() time.Time // 寫入操作使用的時鐘，重播日誌時固定為記錄的時間
+	persist *persistence     // 快照與日誌，未啟用持久化時為 nil
 }
 
 // NewMemoryStorage 創建一個新的記憶體儲存
 func NewMemoryStorage() *MemoryStorage {
 	// 初始化模擬資料
 	users := []models.User{
-		{ID: 1, Username: "admin", Password: "password", Email: "admin@example.com"},
+		{ID: 1, Username: "admin", Password: "password", Email: "admin@example.com", Role: models.RoleAdmin, OrgID: models.DefaultOrganizationID},
 		// 這裡應該有您想要登入的用戶
 	}
 
@@ -103,108 +134,623 @@ func NewMemoryStorage() *MemoryStorage {
 				},
 			},
 		},
+		4: {
+			ID:     4,
+			Name:   "四",
+			SVGUrl: "/assets/characters/si.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 160, Y: 180},
+						{X: 160, Y: 440},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 160, Y: 180},
+						{X: 440, Y: 180},
+						{X: 440, Y: 440},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 270, Y: 180},
+						{X: 210, Y: 330},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 340, Y: 180},
+						{X: 340, Y: 320},
+						{X: 400, Y: 320},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 160, Y: 430},
+						{X: 440, Y: 430},
+					},
+				},
+			},
+		},
+		5: {
+			ID:     5,
+			Name:   "五",
+			SVGUrl: "/assets/characters/wu.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 170, Y: 160},
+						{X: 430, Y: 160},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 290, Y: 160},
+						{X: 260, Y: 420},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 180, Y: 290},
+						{X: 400, Y: 290},
+						{X: 390, Y: 430},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 140, Y: 430},
+						{X: 460, Y: 430},
+					},
+				},
+			},
+		},
+		6: {
+			ID:     6,
+			Name:   "六",
+			SVGUrl: "/assets/characters/liu.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 300, Y: 130},
+						{X: 320, Y: 170},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 150, Y: 230},
+						{X: 300, Y: 230},
+						{X: 450, Y: 230},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 250, Y: 300},
+						{X: 180, Y: 420},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 350, Y: 300},
+						{X: 420, Y: 420},
+					},
+				},
+			},
+		},
+		7: {
+			ID:     7,
+			Name:   "七",
+			SVGUrl: "/assets/characters/qi.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 150, Y: 280},
+						{X: 450, Y: 240},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 250, Y: 150},
+						{X: 250, Y: 420},
+						{X: 450, Y: 420},
+						{X: 450, Y: 380},
+					},
+				},
+			},
+		},
+		8: {
+			ID:     8,
+			Name:   "八",
+			SVGUrl: "/assets/characters/ba.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 270, Y: 180},
+						{X: 230, Y: 320},
+						{X: 150, Y: 430},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 330, Y: 180},
+						{X: 370, Y: 320},
+						{X: 450, Y: 430},
+					},
+				},
+			},
+		},
+		9: {
+			ID:     9,
+			Name:   "九",
+			SVGUrl: "/assets/characters/jiu.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 270, Y: 150},
+						{X: 260, Y: 300},
+						{X: 150, Y: 450},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 150, Y: 250},
+						{X: 380, Y: 250},
+						{X: 380, Y: 420},
+						{X: 450, Y: 420},
+						{X: 450, Y: 380},
+					},
+				},
+			},
+		},
+		10: {
+			ID:     10,
+			Name:   "十",
+			SVGUrl: "/assets/characters/shi.svg",
+			StrokeData: []models.Stroke{
+				{
+					Nodes: []models.Node{
+						{X: 150, Y: 300},
+						{X: 300, Y: 300},
+						{X: 450, Y: 300},
+					},
+				},
+				{
+					Nodes: []models.Node{
+						{X: 300, Y: 150},
+						{X: 300, Y: 300},
+						{X: 300, Y: 450},
+					},
+				},
+			},
+		},
+	}
+
+	// 為參考筆畫標註基本筆畫類型
+	for id, character := range characterDetails {
+		character.StrokeData = classifyStrokes(character.StrokeData)
+		characterDetails[id] = character
+	}
+
+	decks := []models.Deck{
+		{ID: 1, Name: "橫畫入門", CharacterIDs: []int{1, 2, 3}},
+		{ID: 2, Name: "數字一到十", CharacterIDs: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+
+	organizations := []models.Organization{
+		{ID: models.DefaultOrganizationID, Name: "Default", Slug: "default", CreatedAt: time.Now()},
 	}
 
 	return &MemoryStorage{
-		users:            users,
-		characters:       characters,
-		characterDetails: characterDetails,
-		strokeRecords:    []models.StrokeRecord{},
-		userProgress:     make(map[int]models.UserProgress),
-		recordCounter:    1,
+		mu: new(sync.RWMutex),
+		data: &data{
+			organizations:    organizations,
+			users:            users,
+			characters:       characters,
+			characterDetails: characterDetails,
+			decks:            decks,
+			strokeRecords:    make(map[int][]models.StrokeRecord),
+			clientRecordIDs:  make(map[int]map[string]int),
+			strokeStats:      make(map[int]map[strokeKey]models.StrokeStat),
+			userProgress:     make(map[int]models.UserProgress),
+			recordCounter:    1,
+			dailyGoals:       make(map[int]models.DailyGoal),
+			dailyActivity:    make(map[int]map[string]models.DailyActivity),
+			streaks:          make(map[int]models.Streak),
+			achievements:     make(map[int][]models.UserAchievement),
+			aggregates:       make(map[string]map[int]models.PracticeAggregate),
+			history:          make(map[int]map[int64]historySlot),
+			classrooms:       []models.Classroom{},
+			classMembers:     make(map[int][]models.ClassMembership),
+			assignments:      []models.Assignment{},
+			dueProgress:      make(map[int]map[int]models.UserProgress),
+			guardianLinks:    []models.GuardianLink{},
+			auditEntries:     []models.AuditEntry{},
+			now:              time.Now,
+		},
+	}
+}
+
+// GetUsers 獲取組織的所有用戶
+func (s *MemoryStorage) GetUsers(ctx context.Context, orgID int) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range s.users {
+		if user.OrgID == orgID {
+			users = append(users, user)
+		}
 	}
+	return users, nil
 }
 
-// GetUsers 獲取所有用戶
-func (s *MemoryStorage) GetUsers() []models.User {
-	return s.users
+// GetUserByID 根據ID獲取組織內的用戶
+func (s *MemoryStorage) GetUserByID(ctx context.Context, orgID, id int) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.userByID(orgID, id)
 }
 
-// GetUserByID 根據ID獲取用戶
-func (s *MemoryStorage) GetUserByID(id int) (*models.User, error) {
+// userByID 根據ID查找組織內的用戶，呼叫者需持有鎖
+func (s *MemoryStorage) userByID(orgID, id int) (*models.User, error) {
 	for _, user := range s.users {
-		if user.ID == id {
+		if user.ID == id && user.OrgID == orgID {
 			return &user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, fmt.Errorf("user with ID %d: %w", id, storage.ErrNotFound)
 }
 
-// GetUserByUsername 根據用戶名獲取用戶
-func (s *MemoryStorage) GetUserByUsername(username string) (*models.User, error) {
+// GetUserByUsername 根據用戶名獲取組織內的用戶
+func (s *MemoryStorage) GetUserByUsername(ctx context.Context, orgID int, username string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	for _, user := range s.users {
-		if user.Username == username {
+		if user.Username == username && user.OrgID == orgID {
 			return &user, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, fmt.Errorf("user %q: %w", username, storage.ErrNotFound)
 }
 
-// CreateUser 創建新用戶
-func (s *MemoryStorage) CreateUser(user models.User) (*models.User, error) {
+// CreateUser 創建新用戶，用戶名在組織內不可重複
+func (s *MemoryStorage) CreateUser(ctx context.Context, user models.User) (*models.User, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.User, error) {
+			return tx.CreateUser(ctx, user)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// 檢查用戶名是否已存在
 	for _, existingUser := range s.users {
-		if existingUser.Username == user.Username {
-			return nil, errors.New("username already exists")
+		if existingUser.Username == user.Username && existingUser.OrgID == user.OrgID {
+			return nil, fmt.Errorf("username %q: %w", user.Username, storage.ErrConflict)
 		}
 	}
 
 	// 設置新用戶ID
+	saveValue(s.undo, &s.users)
 	user.ID = len(s.users) + 1
 	s.users = append(s.users, user)
+	if err := s.record("CreateUser", newStoredUser(user)); err != nil {
+		return nil, err
+	}
 	return &user, nil
 }
 
-// GetCharacters 獲取所有字元預覽
-func (s *MemoryStorage) GetCharacters() []models.CharacterPreview {
-	return s.characters
+// UpdateUser 更新用戶資料，用戶不可移至其他組織
+func (s *MemoryStorage) UpdateUser(ctx context.Context, user models.User) (*models.User, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.User, error) {
+			return tx.UpdateUser(ctx, user)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existingUser := range s.users {
+		if existingUser.ID == user.ID && existingUser.OrgID == user.OrgID {
+			saveValue(s.undo, &s.users[i])
+			s.users[i] = user
+			if err := s.record("UpdateUser", newStoredUser(user)); err != nil {
+				return nil, err
+			}
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("user with ID %d: %w", user.ID, storage.ErrNotFound)
+}
+
+// GetCharacters 獲取組織可用的所有字元預覽
+func (s *MemoryStorage) GetCharacters(ctx context.Context, orgID int) ([]models.CharacterPreview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	characters := []models.CharacterPreview{}
+	for _, character := range s.characters {
+		if character.OrgID == 0 || character.OrgID == orgID {
+			characters = append(characters, character)
+		}
+	}
+	return characters, nil
 }
 
-// GetCharacterByID 根據ID獲取字元詳情
-func (s *MemoryStorage) GetCharacterByID(id int) (*models.Character, error) {
+// GetCharacterByID 根據ID獲取組織可用的字元詳情
+func (s *MemoryStorage) GetCharacterByID(ctx context.Context, orgID, id int) (*models.Character, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	character, exists := s.characterDetails[id]
-	if !exists {
-		return nil, fmt.Errorf("character with ID %d not found", id)
+	if !exists || (character.OrgID != 0 && character.OrgID != orgID) {
+		return nil, fmt.Errorf("character with ID %d: %w", id, storage.ErrNotFound)
+	}
+	return &character, nil
+}
+
+// CreateCharacter 創建組織的自訂字元
+func (s *MemoryStorage) CreateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Character, error) {
+			return tx.CreateCharacter(ctx, character)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	character.ID = len(s.characters) + 1
+	character.StrokeData = classifyStrokes(character.StrokeData)
+
+	saveValue(s.undo, &s.characters)
+	saveEntry(s.undo, s.characterDetails, character.ID)
+
+	s.characters = append(s.characters, models.CharacterPreview{
+		ID:      character.ID,
+		Name:    character.Name,
+		Preview: character.Name,
+		OrgID:   character.OrgID,
+	})
+	s.characterDetails[character.ID] = character
+	if err := s.record("CreateCharacter", character); err != nil {
+		return nil, err
 	}
 	return &character, nil
 }
 
-// CreateStrokeRecord 創建筆畫記錄
-func (s *MemoryStorage) CreateStrokeRecord(record models.StrokeRecord) (*models.StrokeRecord, error) {
+// UpdateCharacter 更新組織的自訂字元，字元不可移至其他組織
+func (s *MemoryStorage) UpdateCharacter(ctx context.Context, character models.Character) (*models.Character, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Character, error) {
+			return tx.UpdateCharacter(ctx, character)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.characterDetails[character.ID]
+	if !exists || existing.OrgID != character.OrgID {
+		return nil, fmt.Errorf("character with ID %d: %w", character.ID, storage.ErrNotFound)
+	}
+
+	character.StrokeData = classifyStrokes(character.StrokeData)
+	saveEntry(s.undo, s.characterDetails, character.ID)
+	s.characterDetails[character.ID] = character
+	for i, preview := range s.characters {
+		if preview.ID == character.ID {
+			saveValue(s.undo, &s.characters[i])
+			s.characters[i].Name = character.Name
+			s.characters[i].Preview = character.Name
+		}
+	}
+	if err := s.record("UpdateCharacter", character); err != nil {
+		return nil, err
+	}
+	return &character, nil
+}
+
+// GetDecks 獲取組織可用的所有字卡組
+func (s *MemoryStorage) GetDecks(ctx context.Context, orgID int) ([]models.Deck, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	decks := []models.Deck{}
+	for _, deck := range s.decks {
+		if deck.OrgID == 0 || deck.OrgID == orgID {
+			decks = append(decks, deck)
+		}
+	}
+	return decks, nil
+}
+
+// GetDeckByID 根據ID獲取組織可用的字卡組
+func (s *MemoryStorage) GetDeckByID(ctx context.Context, orgID, id int) (*models.Deck, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, deck := range s.decks {
+		if deck.ID == id && (deck.OrgID == 0 || deck.OrgID == orgID) {
+			return &deck, nil
+		}
+	}
+	return nil, fmt.Errorf("deck with ID %d: %w", id, storage.ErrNotFound)
+}
+
+// CreateDeck 創建組織的自訂字卡組
+func (s *MemoryStorage) CreateDeck(ctx context.Context, deck models.Deck) (*models.Deck, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.Deck, error) {
+			return tx.CreateDeck(ctx, deck)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 字卡組只能包含內建字元與同組織的自訂字元
+	for _, id := range deck.CharacterIDs {
+		character, exists := s.characterDetails[id]
+		if !exists || (character.OrgID != 0 && character.OrgID != deck.OrgID) {
+			return nil, fmt.Errorf("deck character %d: %w", id, storage.ErrInvalid)
+		}
+	}
+
+	saveValue(s.undo, &s.decks)
+	deck.ID = len(s.decks) + 1
+	deck.CharacterIDs = append([]int{}, deck.CharacterIDs...)
+	s.decks = append(s.decks, deck)
+	if err := s.record("CreateDeck", deck); err != nil {
+		return nil, err
+	}
+	return &deck, nil
+}
+
+// classifyStrokes 為參考筆畫標註基本筆畫類型
+func classifyStrokes(strokes []models.Stroke) []models.Stroke {
+	classified := make([]models.Stroke, len(strokes))
+	for i, stroke := range strokes {
+		stroke.Type = models.ClassifyStroke(stroke.Nodes)
+		classified[i] = stroke
+	}
+	return classified
+}
+
+// CreateStrokeRecord 創建筆畫記錄，未指定時間時使用當前時間
+// 帶有客戶端ID的記錄以 (用戶, 客戶端ID) 去重
+func (s *MemoryStorage) CreateStrokeRecord(ctx context.Context, record models.StrokeRecord) (*models.StrokeRecord, error) {
+	if s.undo == nil {
+		return autoCommit(ctx, s, func(tx *MemoryStorage) (*models.StrokeRecord, error) {
+			return tx.CreateStrokeRecord(ctx, record)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record.ClientID != "" {
+		if _, exists := s.clientRecordIDs[record.UserID][record.ClientID]; exists {
+			return nil, fmt.Errorf("stroke record with client ID %q: %w", record.ClientID, storage.ErrConflict)
+		}
+	}
+
 	// 設置記錄ID和時間
+	saveValue(s.undo, &s.recordCounter)
 	record.ID = s.recordCounter
-	record.CreatedAt = time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = s.now()
+	}
 	s.recordCounter++
 
-	s.strokeRecords = append(s.strokeRecords, record)
+	saveEntry(s.undo, s.strokeRecords, record.UserID)
+	s.strokeRecords[record.UserID] = append(s.strokeRecords[record.UserID], record)
+	if record.ClientID != "" {
+		ids, exists := s.clientRecordIDs[record.UserID]
+		if !exists {
+			saveEntry(s.undo, s.clientRecordIDs, record.UserID)
+			ids = make(map[string]int)
+			s.clientRecordIDs[record.UserID] = ids
+		}
+		saveEntry(s.undo, ids, record.ClientID)
+		ids[record.ClientID] = record.ID
+	}
+
+	// 更新筆畫彙總與排行榜彙總
+	s.updateStrokeStats(record)
+	s.updateAggregates(record.UserID, record.CreatedAt, func(aggregate *models.PracticeAggregate) {
+		aggregate.Strokes++
+		aggregate.ScoreSum += record.Score
+	})
+	s.updateHistory(record.UserID, record.CreatedAt, func(slot *historySlot) {
+		slot.Attempts++
+		slot.ScoreSum += record.Score
+	})
+
+	if err := s.record("CreateStrokeRecord", record); err != nil {
+		return nil, err
+	}
 	return &record, nil
 }
 
 // GetStrokeRecordsByUserID 獲取用戶的筆畫記錄
-func (s *MemoryStorage) GetStrokeRecordsByUserID(userID int) []models.StrokeRecord {
-	var userRecords []models.StrokeRecord
-	for _, record := range s.strokeRecords {
-		if record.UserID == userID {
-			userRecords = append(userRecords, record)
+func (s *MemoryStorage) GetStrokeRecordsByUserID(ctx context.Context, orgID, userID int) ([]models.StrokeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return nil, err
+	}
+
+	return append([]models.StrokeRecord(nil), s.strokeRecords[userID]...), nil
+}
+
+// GetStrokeRecordByClientID 根據客戶端ID獲取用戶的筆畫記錄
+func (s *MemoryStorage) GetStrokeRecordByClientID(ctx context.Context, orgID, userID int, clientID string) (*models.StrokeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return nil, err
+	}
+
+	id, exists := s.clientRecordIDs[userID][clientID]
+	if exists {
+		for _, record := range s.strokeRecords[userID] {
+			if record.ID == id {
+				return &record, nil
+			}
 		}
 	}
-	return userRecords
+	return nil, fmt.Errorf("stroke record with client ID %q: %w", clientID, storage.ErrNotFound)
 }
 
-// GetUserProgress 獲取用戶進度
-func (s *MemoryStorage) GetUserProgress(userID int) models.UserProgress {
+// GetUserProgress 獲取用戶進度，熟練度依距離上次練習的時間衰減
+func (s *MemoryStorage) GetUserProgress(ctx context.Context, orgID, userID int) (models.UserProgress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return nil, err
+	}
+
 	progress, exists := s.userProgress[userID]
 	if !exists {
-		return models.UserProgress{}
+		return models.UserProgress{}, nil
 	}
-	return progress
+
+	now := time.Now()
+	result := make(models.UserProgress, len(progress))
+	for characterID, charProgress := range progress {
+		charProgress.Mastery = charProgress.MasteryAt(now)
+		result[characterID] = charProgress
+	}
+	return result, nil
 }
 
-// UpdateUserProgress 更新用戶進度
-func (s *MemoryStorage) UpdateUserProgress(userID, characterID, strokeIndex int, score float64) error {
+// UpdateUserProgress 以練習時間更新用戶進度
+func (s *MemoryStorage) UpdateUserProgress(ctx context.Context, orgID, userID, characterID, strokeIndex int, score float64, at time.Time) error {
+	if s.undo == nil {
+		return s.RunInTx(ctx, func(tx storage.Storage) error {
+			return tx.UpdateUserProgress(ctx, orgID, userID, characterID, strokeIndex, score, at)
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.userByID(orgID, userID); err != nil {
+		return err
+	}
+
 	// 確保用戶進度映射存在
 	progress, exists := s.userProgress[userID]
 	if !exists {
+		saveEntry(s.undo, s.userProgress, userID)
 		progress = models.UserProgress{}
 		s.userProgress[userID] = progress
 	}
@@ -212,34 +758,22 @@ func (s *MemoryStorage) UpdateUserProgress(userID, characterID, strokeIndex int,
 	// 獲取字元進度或初始化
 	charProgress, exists := progress[characterID]
 	if !exists {
-		charProgress = models.CharacterProgress{
-			CharacterID: characterID,
-			Attempts:    1,
-			AvgScore:    score,
-			Mastery:     score * 100,
-			LastStroke:  strokeIndex,
-		}
-	} else {
-		// 更新現有進度
-		attempts := charProgress.Attempts
-		avgScore := charProgress.AvgScore
-
-		// 計算新的平均得分
-		newAvgScore := (avgScore*float64(attempts) + score) / float64(attempts+1)
-
-		charProgress.Attempts = attempts + 1
-		charProgress.AvgScore = newAvgScore
-		charProgress.Mastery = newAvgScore * 100
-
-		// 更新最後筆畫索引（如果更大）
-		if strokeIndex > charProgress.LastStroke {
-			charProgress.LastStroke = strokeIndex
-		}
+		charProgress = models.CharacterProgress{CharacterID: characterID}
 	}
+	before := charProgress
+	charProgress.ApplyScore(strokeIndex, score, at)
 
 	// 儲存更新後的進度
+	saveEntry(s.undo, progress, characterID)
 	progress[characterID] = charProgress
-	s.userProgress[userID] = progress
 
-	return nil
+	// 首次熟練時計入排行榜
+	s.updateMasteredHistory(userID, before, charProgress, at)
+	if before.MasteredAt == nil && charProgress.MasteredAt != nil {
+		s.updateAggregates(userID, at, func(aggregate *models.PracticeAggregate) {
+			aggregate.MasteredCharacters++
+		})
+	}
+
+	return s.record("UpdateUserProgress", orgID, userID, characterID, strokeIndex, score, at)
 }
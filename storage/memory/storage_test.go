@@ -0,0 +1,232 @@
+// backend/storage/memory/storage_test.go
+package memory
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/storage/storagetest"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	workers   = 16
+	perWorker = 50
+)
+
+// parallel 同時啟動多個 worker 並等待全部完成
+func parallel(fn func(worker int)) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			fn(worker)
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentCreateUserAssignsUniqueIDs(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	existing, err := s.GetUsers(ctx, models.DefaultOrganizationID)
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+	before := len(existing)
+
+	parallel(func(worker int) {
+		for i := 0; i < perWorker; i++ {
+			user := models.User{
+				Username: fmt.Sprintf("user-%d-%d", worker, i),
+				Password: "pw",
+				Role:     models.RoleStudent,
+				OrgID:    models.DefaultOrganizationID,
+			}
+			if _, err := s.CreateUser(ctx, user); err != nil {
+				t.Errorf("CreateUser(%s): %v", user.Username, err)
+			}
+			s.GetUsers(ctx, models.DefaultOrganizationID)
+		}
+	})
+
+	users, err := s.GetUsers(ctx, models.DefaultOrganizationID)
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+	if got, want := len(users), before+workers*perWorker; got != want {
+		t.Fatalf("got %d users, want %d", got, want)
+	}
+	seen := make(map[int]bool)
+	for _, user := range users {
+		if seen[user.ID] {
+			t.Fatalf("duplicate user ID %d", user.ID)
+		}
+		seen[user.ID] = true
+	}
+}
+
+func TestConcurrentCreateUserRejectsDuplicateUsername(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+
+	var mu sync.Mutex
+	created := 0
+	parallel(func(worker int) {
+		_, err := s.CreateUser(ctx, models.User{Username: "same", Password: "pw", OrgID: models.DefaultOrganizationID})
+		if err == nil {
+			mu.Lock()
+			created++
+			mu.Unlock()
+		}
+	})
+
+	if created != 1 {
+		t.Fatalf("created %d users with the same username, want 1", created)
+	}
+}
+
+func TestConcurrentCreateStrokeRecordAssignsUniqueIDs(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	userIDs := make([]int, 4)
+	for i := range userIDs {
+		user, err := s.CreateUser(ctx, models.User{Username: fmt.Sprintf("writer-%d", i), Password: "pw", OrgID: models.DefaultOrganizationID})
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		userIDs[i] = user.ID
+	}
+
+	parallel(func(worker int) {
+		userID := userIDs[worker%len(userIDs)]
+		for i := 0; i < perWorker; i++ {
+			record := models.StrokeRecord{
+				UserID:      userID,
+				CharacterID: 1,
+				Path:        []models.Node{{X: 0, Y: 0}, {X: 10, Y: 0}},
+				Score:       0.8,
+				ClientID:    fmt.Sprintf("%d-%d", worker, i),
+			}
+			if _, err := s.CreateStrokeRecord(ctx, record); err != nil {
+				t.Errorf("CreateStrokeRecord: %v", err)
+			}
+			s.GetStrokeRecordsByUserID(ctx, models.DefaultOrganizationID, userID)
+			s.GetStrokeStats(ctx, models.DefaultOrganizationID, []int{userID})
+		}
+	})
+
+	seen := make(map[int]bool)
+	total := 0
+	for _, userID := range userIDs {
+		records, err := s.GetStrokeRecordsByUserID(ctx, models.DefaultOrganizationID, userID)
+		if err != nil {
+			t.Fatalf("GetStrokeRecordsByUserID: %v", err)
+		}
+		for _, record := range records {
+			if seen[record.ID] {
+				t.Fatalf("duplicate record ID %d", record.ID)
+			}
+			seen[record.ID] = true
+			total++
+		}
+	}
+	if want := workers * perWorker; total != want {
+		t.Fatalf("got %d records, want %d", total, want)
+	}
+}
+
+func TestConcurrentUpdateUserProgressCountsEveryAttempt(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	const userID, characterID = 1, 1
+
+	parallel(func(worker int) {
+		for i := 0; i < perWorker; i++ {
+			if err := s.UpdateUserProgress(ctx, models.DefaultOrganizationID, userID, characterID, i%3, 0.9, time.Now()); err != nil {
+				t.Errorf("UpdateUserProgress: %v", err)
+			}
+			s.GetUserProgress(ctx, models.DefaultOrganizationID, userID)
+		}
+	})
+
+	userProgress, err := s.GetUserProgress(ctx, models.DefaultOrganizationID, userID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	progress := userProgress[characterID]
+	if want := workers * perWorker; progress.Attempts != want {
+		t.Fatalf("got %d attempts, want %d", progress.Attempts, want)
+	}
+}
+
+func TestConcurrentTransactionsRollBackIndependently(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	const userID, characterID = 1, 1
+	failure := errors.New("fail")
+
+	parallel(func(worker int) {
+		for i := 0; i < perWorker; i++ {
+			err := s.RunInTx(ctx, func(tx storage.Storage) error {
+				record, err := tx.CreateStrokeRecord(ctx, models.StrokeRecord{
+					UserID:      userID,
+					CharacterID: characterID,
+					Path:        []models.Node{{X: 0, Y: 0}, {X: 10, Y: 0}},
+					Score:       0.8,
+				})
+				if err != nil {
+					return err
+				}
+				if err := tx.UpdateUserProgress(ctx, models.DefaultOrganizationID, userID, characterID, 0, record.Score, record.CreatedAt); err != nil {
+					return err
+				}
+				if worker%2 == 1 {
+					return failure
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, failure) {
+				t.Errorf("RunInTx: %v", err)
+			}
+			s.GetUserProgress(ctx, models.DefaultOrganizationID, userID)
+		}
+	})
+
+	want := workers / 2 * perWorker
+	records, err := s.GetStrokeRecordsByUserID(ctx, models.DefaultOrganizationID, userID)
+	if err != nil || len(records) != want {
+		t.Fatalf("got %d records (%v), want %d", len(records), err, want)
+	}
+	userProgress, err := s.GetUserProgress(ctx, models.DefaultOrganizationID, userID)
+	if err != nil || userProgress[characterID].Attempts != want {
+		t.Fatalf("got %d attempts (%v), want %d", userProgress[characterID].Attempts, err, want)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Storage { return NewMemoryStorage() })
+}
+
+func TestSeededDecksHaveStrokeData(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStorage()
+	decks, err := s.GetDecks(ctx, models.DefaultOrganizationID)
+	if err != nil {
+		t.Fatalf("GetDecks: %v", err)
+	}
+	// 卡組中的字元都必須可以練習，否則卡組成就與推薦無法達成
+	for _, deck := range decks {
+		for _, id := range deck.CharacterIDs {
+			character, err := s.GetCharacterByID(ctx, models.DefaultOrganizationID, id)
+			if err != nil || len(character.StrokeData) == 0 {
+				t.Errorf("deck %q: character %d has no stroke data (%v)", deck.Name, id, err)
+			}
+		}
+	}
+}
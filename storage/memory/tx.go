@@ -0,0 +1,121 @@
+// backend/storage/memory/tx.go
+package memory
+
+import (
+	"backend/storage"
+	"context"
+)
+
+// rwLocker 讀寫鎖，交易內的儲存使用不加鎖的實作
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// noLock 交易期間外層儲存已持有寫鎖，交易內的操作不再加鎖
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+// undoLog 依序記錄交易中每次寫入前的狀態，回滾時反向還原
+type undoLog struct {
+	steps []func()
+	ops   []journalOp // 交易提交時寫入日誌的操作
+}
+
+// rollback 反向執行所有還原操作
+func (l *undoLog) rollback() {
+	for i := len(l.steps) - 1; i >= 0; i-- {
+		l.steps[i]()
+	}
+	l.steps = nil
+	l.ops = nil
+}
+
+// RunInTx 在單一交易中執行 fn
+// 交易期間持有寫鎖，fn 返回錯誤、發生 panic 或 context 已取消時還原交易內的所有寫入。
+// 已在交易中呼叫時直接併入外層交易。
+func (s *MemoryStorage) RunInTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	if s.undo != nil {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &MemoryStorage{mu: noLock{}, undo: &undoLog{}, data: s.data}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.undo.rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.persist != nil && len(tx.undo.ops) > 0 {
+		if err := s.persist.append(tx.undo.ops); err != nil {
+			return err
+		}
+	}
+	committed = true
+	return nil
+}
+
+// autoCommit 以只含單次寫入的交易執行交易外呼叫的寫入方法
+// 寫入的操作在提交時才附加至日誌，日誌寫入失敗時與一般交易一樣回滾記憶體中的變更。
+func autoCommit[T any](ctx context.Context, s *MemoryStorage, write func(tx *MemoryStorage) (T, error)) (T, error) {
+	var result T
+	err := s.RunInTx(ctx, func(tx storage.Storage) error {
+		var err error
+		result, err = write(tx.(*MemoryStorage))
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// saveValue 記錄變數的原值，也用於只會附加元素的切片
+func saveValue[T any](l *undoLog, p *T) {
+	if l == nil {
+		return
+	}
+	old := *p
+	l.steps = append(l.steps, func() { *p = old })
+}
+
+// saveSlice 複製切片內容，用於會就地移動或刪除元素的切片
+func saveSlice[T any](l *undoLog, p *[]T) {
+	if l == nil {
+		return
+	}
+	old := append([]T(nil), *p...)
+	l.steps = append(l.steps, func() { *p = old })
+}
+
+// saveEntry 記錄映射中鍵的原值，回滾時還原或刪除該鍵
+func saveEntry[K comparable, V any](l *undoLog, m map[K]V, key K) {
+	if l == nil {
+		return
+	}
+	old, existed := m[key]
+	l.steps = append(l.steps, func() {
+		if existed {
+			m[key] = old
+		} else {
+			delete(m, key)
+		}
+	})
+}
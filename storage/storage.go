@@ -3,25 +3,102 @@ package storage
 
 import (
 	"backend/models"
+	"context"
+	"time"
 )
 
 // Storage 定義存儲介面
+//
+// 用戶、班級、作業、監護人連結、字元與字卡組的查詢都以 orgID 篩選，不同組織的資料互不可見。
+// 以用戶ID為鍵的查詢與寫入（筆畫記錄、進度、目標、每日統計、成就等）在用戶不屬於該組織時返回 ErrNotFound。
+// 建立筆畫記錄、加入班級與保存作業到期進度不接受 orgID，呼叫者需先以組織載入用戶、班級或作業。
+// 所有方法都接受請求的 context 並返回錯誤，查無資料時返回包裝 ErrNotFound 的錯誤，
+// 資料重複時返回包裝 ErrConflict 的錯誤。
+// 建立資料時若已指定建立時間則保留，供備份還原使用，否則使用當前時間。
 type Storage interface {
+	// RunInTx 在單一交易中執行 fn，fn 內的讀寫必須透過 tx 進行；
+	// fn 返回錯誤時 tx 上的所有寫入一併回滾，否則一併提交。
+	RunInTx(ctx context.Context, fn func(tx Storage) error) error
+
+	// 組織相關
+	GetOrganizations(ctx context.Context) ([]models.Organization, error)
+	CreateOrganization(ctx context.Context, org models.Organization) (*models.Organization, error)
+	GetOrganizationByID(ctx context.Context, id int) (*models.Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*models.Organization, error)
+
 	// 用戶相關
-	GetUsers() []models.User
-	GetUserByID(id int) (*models.User, error)
-	GetUserByUsername(username string) (*models.User, error)
-	CreateUser(user models.User) (*models.User, error)
+	GetUsers(ctx context.Context, orgID int) ([]models.User, error)
+	GetUserByID(ctx context.Context, orgID, id int) (*models.User, error)
+	GetUserByUsername(ctx context.Context, orgID int, username string) (*models.User, error)
+	CreateUser(ctx context.Context, user models.User) (*models.User, error)
+	UpdateUser(ctx context.Context, user models.User) (*models.User, error)
+	GetUsersDueForDeletion(ctx context.Context, requestedBefore time.Time) ([]models.User, error)
+	PurgeUser(ctx context.Context, orgID, userID int, at time.Time) error
 
-	// 字元相關
-	GetCharacters() []models.CharacterPreview
-	GetCharacterByID(id int) (*models.Character, error)
+	// 字元相關（包含共用的內建字元與組織的自訂字元）
+	GetCharacters(ctx context.Context, orgID int) ([]models.CharacterPreview, error)
+	GetCharacterByID(ctx context.Context, orgID, id int) (*models.Character, error)
+	CreateCharacter(ctx context.Context, character models.Character) (*models.Character, error)
+	UpdateCharacter(ctx context.Context, character models.Character) (*models.Character, error)
+	GetDecks(ctx context.Context, orgID int) ([]models.Deck, error)
+	GetDeckByID(ctx context.Context, orgID, id int) (*models.Deck, error)
+	CreateDeck(ctx context.Context, deck models.Deck) (*models.Deck, error)
 
 	// 筆畫記錄相關
-	CreateStrokeRecord(record models.StrokeRecord) (*models.StrokeRecord, error)
-	GetStrokeRecordsByUserID(userID int) []models.StrokeRecord
+	CreateStrokeRecord(ctx context.Context, record models.StrokeRecord) (*models.StrokeRecord, error)
+	GetStrokeRecordsByUserID(ctx context.Context, orgID, userID int) ([]models.StrokeRecord, error)
+	GetStrokeRecordByClientID(ctx context.Context, orgID, userID int, clientID string) (*models.StrokeRecord, error)
+	QueryStrokeRecords(ctx context.Context, orgID int, query models.StrokeRecordQuery) (*models.StrokeRecordPage, error)
+	GetStrokeStats(ctx context.Context, orgID int, userIDs []int) ([]models.StrokeStat, error)
 
 	// 用戶進度相關
-	GetUserProgress(userID int) models.UserProgress
-	UpdateUserProgress(userID, characterID, strokeIndex int, score float64) error
+	GetUserProgress(ctx context.Context, orgID, userID int) (models.UserProgress, error)
+	UpdateUserProgress(ctx context.Context, orgID, userID, characterID, strokeIndex int, score float64, at time.Time) error
+	GetProgressHistory(ctx context.Context, orgID int, query models.ProgressHistoryQuery) ([]models.ProgressHistoryPoint, error)
+
+	// 每日目標與連續練習相關
+	GetDailyGoal(ctx context.Context, orgID, userID int) (models.DailyGoal, error)
+	SetDailyGoal(ctx context.Context, goal models.DailyGoal) error
+	GetDailyActivity(ctx context.Context, orgID, userID int, date string) (models.DailyActivity, error)
+	RecordDailyActivity(ctx context.Context, orgID, userID, characterID int, date string, at time.Time) error
+	GetStreak(ctx context.Context, orgID, userID int) (models.Streak, error)
+
+	// 成就相關
+	GetUserAchievements(ctx context.Context, orgID, userID int) ([]models.UserAchievement, error)
+	UnlockAchievement(ctx context.Context, orgID, userID int, achievementID string, at time.Time) (bool, error)
+
+	// 排行榜相關
+	GetLeaderboard(ctx context.Context, query models.LeaderboardQuery) ([]models.LeaderboardEntry, error)
+
+	// 班級相關
+	CreateClassroom(ctx context.Context, classroom models.Classroom) (*models.Classroom, error)
+	GetClassroomByID(ctx context.Context, orgID, id int) (*models.Classroom, error)
+	GetClassroomByCode(ctx context.Context, orgID int, code string) (*models.Classroom, error)
+	GetClassroomsByTeacherID(ctx context.Context, orgID, teacherID int) ([]models.Classroom, error)
+	GetClassroomsByStudentID(ctx context.Context, orgID, userID int) ([]models.Classroom, error)
+	AddClassMember(ctx context.Context, classID, userID int) (*models.ClassMembership, error)
+	GetClassMembers(ctx context.Context, orgID, classID int) ([]models.ClassMembership, error)
+
+	// 作業相關
+	CreateAssignment(ctx context.Context, assignment models.Assignment) (*models.Assignment, error)
+	GetAssignmentByID(ctx context.Context, orgID, id int) (*models.Assignment, error)
+	GetAssignmentsByClassID(ctx context.Context, orgID, classID int) ([]models.Assignment, error)
+	UpdateAssignment(ctx context.Context, assignment models.Assignment) (*models.Assignment, error)
+	DeleteAssignment(ctx context.Context, orgID, id int) error
+	// 作業到期時學生在作業字元上的進度，每位學生只保存第一次，作業的到期時間或字元變更時清除
+	SaveAssignmentDueProgress(ctx context.Context, assignmentID, userID int, progress models.UserProgress) (bool, error)
+	GetAssignmentDueProgress(ctx context.Context, orgID, assignmentID int) (map[int]models.UserProgress, error)
+	HasAssignmentDueProgress(ctx context.Context, orgID, assignmentID, userID int) (bool, error)
+
+	// 監護人相關
+	CreateGuardianLink(ctx context.Context, link models.GuardianLink) (*models.GuardianLink, error)
+	GetGuardianLinkByID(ctx context.Context, orgID, id int) (*models.GuardianLink, error)
+	GetGuardianLinksByGuardianID(ctx context.Context, orgID, guardianID int) ([]models.GuardianLink, error)
+	GetGuardianLinksByChildID(ctx context.Context, orgID, childID int) ([]models.GuardianLink, error)
+	UpdateGuardianLink(ctx context.Context, link models.GuardianLink) (*models.GuardianLink, error)
+	DeleteGuardianLink(ctx context.Context, orgID, id int) error
+
+	// 稽核記錄相關
+	CreateAuditEntry(ctx context.Context, entry models.AuditEntry) (*models.AuditEntry, error)
+	GetAuditEntries(ctx context.Context, orgID int) ([]models.AuditEntry, error)
 }
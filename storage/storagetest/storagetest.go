@@ -0,0 +1,822 @@
+// backend/storage/storagetest/storagetest.go
+
+// Package storagetest 提供所有 storage.Storage 實作共用的一致性測試
+//
+// 新的儲存後端只需在自己的測試中呼叫 Run：
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func() storage.Storage { return NewXStorage() })
+//	}
+package storagetest
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// Factory 為每個子測試建立一個全新的儲存
+type Factory func() storage.Storage
+
+// Run 對儲存實作執行所有一致性測試
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"OrganizationSlugIsUnique", testOrganizationSlugIsUnique},
+		{"GetOrganizations", testGetOrganizations},
+		{"CreateUserAssignsIDs", testCreateUserAssignsIDs},
+		{"UsernameIsUniquePerOrganization", testUsernameIsUniquePerOrganization},
+		{"UsersAreScopedToOrganization", testUsersAreScopedToOrganization},
+		{"PurgedUsernamesStayUnique", testPurgedUsernamesStayUnique},
+		{"UpdateUser", testUpdateUser},
+		{"UserDataIsScopedToOrganization", testUserDataIsScopedToOrganization},
+		{"DeckCharactersMustBeVisible", testDeckCharactersMustBeVisible},
+		{"StrokeRecordOrdering", testStrokeRecordOrdering},
+		{"StrokeRecordClientID", testStrokeRecordClientID},
+		{"QueryStrokeRecordsPagination", testQueryStrokeRecordsPagination},
+		{"ProgressMath", testProgressMath},
+		{"ProgressHistory", testProgressHistory},
+		{"AssignmentDueProgress", testAssignmentDueProgress},
+		{"UnlockAchievementOnce", testUnlockAchievementOnce},
+		{"NotFoundErrors", testNotFoundErrors},
+		{"TransactionCommits", testTransactionCommits},
+		{"TransactionRollsBack", testTransactionRollsBack},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage())
+		})
+	}
+}
+
+// mustCreateOrganization 建立測試用組織
+func mustCreateOrganization(t *testing.T, s storage.Storage, slug string) *models.Organization {
+	t.Helper()
+	ctx := context.Background()
+	org, err := s.CreateOrganization(ctx, models.Organization{Name: slug, Slug: slug})
+	if err != nil {
+		t.Fatalf("CreateOrganization(%q): %v", slug, err)
+	}
+	return org
+}
+
+// mustCreateUser 建立測試用用戶
+func mustCreateUser(t *testing.T, s storage.Storage, orgID int, username string) *models.User {
+	t.Helper()
+	ctx := context.Background()
+	user, err := s.CreateUser(ctx, models.User{Username: username, Password: "pw", Role: models.RoleStudent, OrgID: orgID})
+	if err != nil {
+		t.Fatalf("CreateUser(%q): %v", username, err)
+	}
+	return user
+}
+
+// mustCreateStrokeRecord 建立測試用筆畫記錄
+func mustCreateStrokeRecord(t *testing.T, s storage.Storage, record models.StrokeRecord) *models.StrokeRecord {
+	t.Helper()
+	ctx := context.Background()
+	if record.Path == nil {
+		record.Path = []models.Node{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	}
+	created, err := s.CreateStrokeRecord(ctx, record)
+	if err != nil {
+		t.Fatalf("CreateStrokeRecord: %v", err)
+	}
+	return created
+}
+
+func testOrganizationSlugIsUnique(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "conformance")
+	if org.ID <= 0 {
+		t.Fatalf("organization ID = %d, want positive", org.ID)
+	}
+	if _, err := s.CreateOrganization(ctx, models.Organization{Name: "again", Slug: "conformance"}); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("creating an organization with a duplicate slug = %v, want ErrConflict", err)
+	}
+
+	got, err := s.GetOrganizationBySlug(ctx, "conformance")
+	if err != nil || got.ID != org.ID {
+		t.Fatalf("GetOrganizationBySlug = %v, %v; want ID %d", got, err, org.ID)
+	}
+}
+
+func testGetOrganizations(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	created := mustCreateOrganization(t, s, "listed")
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	restored, err := s.CreateOrganization(ctx, models.Organization{Name: "restored", Slug: "restored", CreatedAt: createdAt})
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	if !restored.CreatedAt.Equal(createdAt) {
+		t.Fatalf("CreatedAt = %v, want the given %v", restored.CreatedAt, createdAt)
+	}
+
+	orgs, err := s.GetOrganizations(ctx)
+	if err != nil {
+		t.Fatalf("GetOrganizations: %v", err)
+	}
+	found := map[int]bool{}
+	for i, org := range orgs {
+		if i > 0 && org.ID <= orgs[i-1].ID {
+			t.Fatalf("organizations not ordered by ID: %v", orgs)
+		}
+		found[org.ID] = true
+	}
+	if !found[created.ID] || !found[restored.ID] {
+		t.Fatalf("GetOrganizations = %v, want IDs %d and %d", orgs, created.ID, restored.ID)
+	}
+}
+
+func testCreateUserAssignsIDs(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "ids")
+	first := mustCreateUser(t, s, org.ID, "first")
+	second := mustCreateUser(t, s, org.ID, "second")
+
+	if first.ID <= 0 || second.ID <= 0 || first.ID == second.ID {
+		t.Fatalf("user IDs = %d, %d; want distinct positive IDs", first.ID, second.ID)
+	}
+
+	got, err := s.GetUserByID(ctx, org.ID, second.ID)
+	if err != nil || got.Username != "second" {
+		t.Fatalf("GetUserByID = %v, %v; want second", got, err)
+	}
+	got, err = s.GetUserByUsername(ctx, org.ID, "first")
+	if err != nil || got.ID != first.ID {
+		t.Fatalf("GetUserByUsername = %v, %v; want ID %d", got, err, first.ID)
+	}
+}
+
+func testUsernameIsUniquePerOrganization(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	orgA := mustCreateOrganization(t, s, "org-a")
+	orgB := mustCreateOrganization(t, s, "org-b")
+	mustCreateUser(t, s, orgA.ID, "alice")
+
+	if _, err := s.CreateUser(ctx, models.User{Username: "alice", Password: "pw", OrgID: orgA.ID}); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("creating a duplicate username in the same organization = %v, want ErrConflict", err)
+	}
+	mustCreateUser(t, s, orgB.ID, "alice")
+}
+
+func testPurgedUsernamesStayUnique(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "purge")
+	user := mustCreateUser(t, s, org.ID, "zoe")
+	// 例如由備份還原而來、已使用匿名用戶名的帳號
+	squatter := mustCreateUser(t, s, org.ID, models.AnonymizedUsername(user.ID, 1))
+
+	if err := s.PurgeUser(ctx, org.ID, user.ID, time.Now()); err != nil {
+		t.Fatalf("PurgeUser: %v", err)
+	}
+	purged, err := s.GetUserByID(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if purged.Username == squatter.Username || !models.IsReservedUsername(purged.Username) {
+		t.Fatalf("purged username = %q, want a reserved name other than %q", purged.Username, squatter.Username)
+	}
+	if found, err := s.GetUserByUsername(ctx, org.ID, squatter.Username); err != nil || found.ID != squatter.ID {
+		t.Fatalf("GetUserByUsername(%q) = %+v, %v; want user %d", squatter.Username, found, err, squatter.ID)
+	}
+}
+
+func testUsersAreScopedToOrganization(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	orgA := mustCreateOrganization(t, s, "scope-a")
+	orgB := mustCreateOrganization(t, s, "scope-b")
+	user := mustCreateUser(t, s, orgA.ID, "bob")
+
+	if _, err := s.GetUserByID(ctx, orgB.ID, user.ID); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("GetUserByID from another organization = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetUserByUsername(ctx, orgB.ID, "bob"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("GetUserByUsername from another organization = %v, want ErrNotFound", err)
+	}
+	users, err := s.GetUsers(ctx, orgB.ID)
+	if err != nil {
+		t.Fatalf("GetUsers: %v", err)
+	}
+	for _, other := range users {
+		if other.ID == user.ID {
+			t.Fatal("GetUsers returned a user from another organization")
+		}
+	}
+}
+
+func testUpdateUser(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "update")
+	user := mustCreateUser(t, s, org.ID, "carol")
+
+	user.Email = "carol@example.com"
+	user.LeaderboardOptOut = true
+	if _, err := s.UpdateUser(ctx, *user); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	got, err := s.GetUserByID(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if got.Email != "carol@example.com" || !got.LeaderboardOptOut {
+		t.Fatalf("updated user = %+v, changes not persisted", got)
+	}
+}
+
+func testUserDataIsScopedToOrganization(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "home")
+	other := mustCreateOrganization(t, s, "elsewhere")
+	teacher := mustCreateUser(t, s, org.ID, "teacher")
+	student := mustCreateUser(t, s, org.ID, "student")
+	record := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: student.ID, CharacterID: 1, Score: 0.8, ClientID: "scoped"})
+	if err := s.UpdateUserProgress(ctx, org.ID, student.ID, 1, 0, record.Score, record.CreatedAt); err != nil {
+		t.Fatalf("UpdateUserProgress: %v", err)
+	}
+	classroom, err := s.CreateClassroom(ctx, models.Classroom{OrgID: org.ID, Name: "1A", TeacherID: teacher.ID, EnrolmentCode: "SCOPE1"})
+	if err != nil {
+		t.Fatalf("CreateClassroom: %v", err)
+	}
+	if _, err := s.AddClassMember(ctx, classroom.ID, student.ID); err != nil {
+		t.Fatalf("AddClassMember: %v", err)
+	}
+	assignment, err := s.CreateAssignment(ctx, models.Assignment{ClassID: classroom.ID, Title: "一", CharacterIDs: []int{1}, DueAt: time.Now()})
+	if err != nil {
+		t.Fatalf("CreateAssignment: %v", err)
+	}
+	link, err := s.CreateGuardianLink(ctx, models.GuardianLink{GuardianID: teacher.ID, ChildID: student.ID, Status: models.GuardianLinkPending})
+	if err != nil {
+		t.Fatalf("CreateGuardianLink: %v", err)
+	}
+
+	// 以其他組織查詢時，用戶與班級視為不存在
+	checks := map[string]error{}
+	_, checks["GetStrokeRecordsByUserID"] = s.GetStrokeRecordsByUserID(ctx, other.ID, student.ID)
+	_, checks["QueryStrokeRecords"] = s.QueryStrokeRecords(ctx, other.ID, models.StrokeRecordQuery{UserID: student.ID})
+	_, checks["GetUserProgress"] = s.GetUserProgress(ctx, other.ID, student.ID)
+	_, checks["GetProgressHistory"] = s.GetProgressHistory(ctx, other.ID, models.ProgressHistoryQuery{
+		UserID: student.ID, From: record.CreatedAt.Add(-time.Hour), To: record.CreatedAt.Add(time.Hour), Interval: models.HistoryIntervalDay,
+	})
+	_, checks["GetDailyGoal"] = s.GetDailyGoal(ctx, other.ID, student.ID)
+	_, checks["GetStreak"] = s.GetStreak(ctx, other.ID, student.ID)
+	_, checks["GetUserAchievements"] = s.GetUserAchievements(ctx, other.ID, student.ID)
+	_, checks["GetStrokeRecordByClientID"] = s.GetStrokeRecordByClientID(ctx, other.ID, student.ID, "scoped")
+	_, checks["GetStrokeStats"] = s.GetStrokeStats(ctx, other.ID, []int{student.ID})
+	_, checks["GetDailyActivity"] = s.GetDailyActivity(ctx, other.ID, student.ID, record.CreatedAt.Format(models.DateLayout))
+	checks["UpdateUserProgress"] = s.UpdateUserProgress(ctx, other.ID, student.ID, 1, 0, 0.5, record.CreatedAt)
+	checks["RecordDailyActivity"] = s.RecordDailyActivity(ctx, other.ID, student.ID, 1, record.CreatedAt.Format(models.DateLayout), record.CreatedAt)
+	_, checks["UnlockAchievement"] = s.UnlockAchievement(ctx, other.ID, student.ID, "strokes_100", record.CreatedAt)
+	_, checks["GetClassMembers"] = s.GetClassMembers(ctx, other.ID, classroom.ID)
+	_, checks["GetAssignmentsByClassID"] = s.GetAssignmentsByClassID(ctx, other.ID, classroom.ID)
+	_, checks["GetGuardianLinkByID"] = s.GetGuardianLinkByID(ctx, other.ID, link.ID)
+	_, checks["HasAssignmentDueProgress"] = s.HasAssignmentDueProgress(ctx, other.ID, assignment.ID, student.ID)
+	checks["DeleteAssignment"] = s.DeleteAssignment(ctx, other.ID, assignment.ID)
+	checks["DeleteGuardianLink"] = s.DeleteGuardianLink(ctx, other.ID, link.ID)
+	for name, err := range checks {
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s from another organization = %v, want ErrNotFound", name, err)
+		}
+	}
+
+	lists := map[string]func(orgID int) (int, error){
+		"GetClassroomsByTeacherID": func(orgID int) (int, error) {
+			classrooms, err := s.GetClassroomsByTeacherID(ctx, orgID, teacher.ID)
+			return len(classrooms), err
+		},
+		"GetClassroomsByStudentID": func(orgID int) (int, error) {
+			classrooms, err := s.GetClassroomsByStudentID(ctx, orgID, student.ID)
+			return len(classrooms), err
+		},
+		"GetGuardianLinksByGuardianID": func(orgID int) (int, error) {
+			links, err := s.GetGuardianLinksByGuardianID(ctx, orgID, teacher.ID)
+			return len(links), err
+		},
+		"GetGuardianLinksByChildID": func(orgID int) (int, error) {
+			links, err := s.GetGuardianLinksByChildID(ctx, orgID, student.ID)
+			return len(links), err
+		},
+	}
+	for name, list := range lists {
+		if n, err := list(org.ID); err != nil || n != 1 {
+			t.Errorf("%s in the same organization = %d, %v; want 1", name, n, err)
+		}
+		if n, err := list(other.ID); err != nil || n != 0 {
+			t.Errorf("%s from another organization = %d, %v; want none", name, n, err)
+		}
+	}
+
+	// 其他組織的刪除不影響原資料
+	if got, err := s.GetAssignmentsByClassID(ctx, org.ID, classroom.ID); err != nil || len(got) != 1 {
+		t.Fatalf("assignments after a cross-organization delete = %d, %v; want 1", len(got), err)
+	}
+	if _, err := s.GetGuardianLinkByID(ctx, org.ID, link.ID); err != nil {
+		t.Fatalf("guardian link after a cross-organization delete: %v", err)
+	}
+}
+
+func testDeckCharactersMustBeVisible(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "decks")
+	other := mustCreateOrganization(t, s, "other-decks")
+	custom, err := s.CreateCharacter(ctx, models.Character{Name: "人", OrgID: other.ID, StrokeData: []models.Stroke{{Nodes: []models.Node{{X: 0, Y: 0}, {X: 10, Y: 10}}}}})
+	if err != nil {
+		t.Fatalf("CreateCharacter: %v", err)
+	}
+
+	for name, ids := range map[string][]int{"unknown": {1, 999999}, "another organization's": {custom.ID}} {
+		if _, err := s.CreateDeck(ctx, models.Deck{Name: name, CharacterIDs: ids, OrgID: org.ID}); !errors.Is(err, storage.ErrInvalid) {
+			t.Errorf("CreateDeck with an %s character = %v, want ErrInvalid", name, err)
+		}
+	}
+	if _, err := s.CreateDeck(ctx, models.Deck{Name: "mixed", CharacterIDs: []int{1, custom.ID}, OrgID: other.ID}); err != nil {
+		t.Errorf("CreateDeck with built-in and own characters: %v", err)
+	}
+}
+
+func testStrokeRecordOrdering(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "ordering")
+	user := mustCreateUser(t, s, org.ID, "erin")
+	other := mustCreateUser(t, s, org.ID, "frank")
+	clientTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var created []*models.StrokeRecord
+	for i := 0; i < 5; i++ {
+		record := models.StrokeRecord{UserID: user.ID, CharacterID: 1, StrokeIndex: i, Score: 0.5}
+		if i == 2 {
+			record.CreatedAt = clientTime
+		}
+		created = append(created, mustCreateStrokeRecord(t, s, record))
+	}
+
+	for i, record := range created {
+		if i > 0 && record.ID <= created[i-1].ID {
+			t.Fatalf("record IDs not increasing: %d after %d", record.ID, created[i-1].ID)
+		}
+		if record.CreatedAt.IsZero() {
+			t.Fatalf("record %d has no creation time", record.ID)
+		}
+	}
+	if !created[2].CreatedAt.Equal(clientTime) {
+		t.Fatalf("CreatedAt = %v, want the supplied %v", created[2].CreatedAt, clientTime)
+	}
+
+	records, err := s.GetStrokeRecordsByUserID(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetStrokeRecordsByUserID: %v", err)
+	}
+	if len(records) != len(created) {
+		t.Fatalf("got %d records, want %d", len(records), len(created))
+	}
+	for i, record := range records {
+		if record.ID != created[i].ID {
+			t.Fatalf("record %d has ID %d, want %d", i, record.ID, created[i].ID)
+		}
+	}
+	if others, err := s.GetStrokeRecordsByUserID(ctx, org.ID, other.ID); err != nil || len(others) != 0 {
+		t.Fatalf("GetStrokeRecordsByUserID for another user = %d records, %v; want none", len(others), err)
+	}
+}
+
+func testStrokeRecordClientID(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "client-ids")
+	user := mustCreateUser(t, s, org.ID, "kate")
+	other := mustCreateUser(t, s, org.ID, "liam")
+	record := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Score: 0.7, ClientID: "abc"})
+
+	if _, err := s.CreateStrokeRecord(ctx, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Path: record.Path, ClientID: "abc"}); !errors.Is(err, storage.ErrConflict) {
+		t.Fatalf("creating a record with a duplicate client ID = %v, want ErrConflict", err)
+	}
+	mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: other.ID, CharacterID: 1, ClientID: "abc"})
+
+	got, err := s.GetStrokeRecordByClientID(ctx, org.ID, user.ID, "abc")
+	if err != nil || got.ID != record.ID {
+		t.Fatalf("GetStrokeRecordByClientID = %v, %v; want ID %d", got, err, record.ID)
+	}
+	if _, err := s.GetStrokeRecordByClientID(ctx, org.ID, user.ID, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("GetStrokeRecordByClientID for a missing client ID = %v, want ErrNotFound", err)
+	}
+}
+
+func testQueryStrokeRecordsPagination(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "pagination")
+	user := mustCreateUser(t, s, org.ID, "grace")
+	const total = 7
+	for i := 0; i < total; i++ {
+		mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: 1 + i%2, Score: float64(i) / total})
+	}
+
+	query := models.StrokeRecordQuery{UserID: user.ID, Limit: 3}
+	lastID := 0
+	count := 0
+	for pages := 0; ; pages++ {
+		if pages > total {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := s.QueryStrokeRecords(ctx, org.ID, query)
+		if err != nil {
+			t.Fatalf("QueryStrokeRecords: %v", err)
+		}
+		if len(page.Records) > query.Limit {
+			t.Fatalf("page has %d records, limit %d", len(page.Records), query.Limit)
+		}
+		for _, record := range page.Records {
+			if record.ID <= lastID {
+				t.Fatalf("record ID %d returned after %d", record.ID, lastID)
+			}
+			lastID = record.ID
+			count++
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	if count != total {
+		t.Fatalf("paged through %d records, want %d", count, total)
+	}
+
+	characterID := 2
+	page, err := s.QueryStrokeRecords(ctx, org.ID, models.StrokeRecordQuery{UserID: user.ID, CharacterID: &characterID})
+	if err != nil {
+		t.Fatalf("QueryStrokeRecords: %v", err)
+	}
+	for _, record := range page.Records {
+		if record.CharacterID != characterID {
+			t.Fatalf("filter returned character %d, want %d", record.CharacterID, characterID)
+		}
+	}
+
+	if _, err := s.QueryStrokeRecords(ctx, org.ID, models.StrokeRecordQuery{UserID: user.ID, Cursor: "not a cursor!"}); !errors.Is(err, storage.ErrInvalid) {
+		t.Fatalf("QueryStrokeRecords with an invalid cursor = %v, want ErrInvalid", err)
+	}
+}
+
+func testProgressMath(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "progress")
+	user := mustCreateUser(t, s, org.ID, "heidi")
+	if progress, err := s.GetUserProgress(ctx, org.ID, user.ID); err != nil || len(progress) != 0 {
+		t.Fatalf("GetUserProgress for a new user = %v, %v; want empty", progress, err)
+	}
+
+	at := time.Now()
+	scores := []struct {
+		stroke int
+		score  float64
+	}{{0, 1.0}, {2, 0.5}, {1, 0.9}}
+
+	for _, sc := range scores {
+		if err := s.UpdateUserProgress(ctx, org.ID, user.ID, 4, sc.stroke, sc.score, at); err != nil {
+			t.Fatalf("UpdateUserProgress: %v", err)
+		}
+	}
+
+	progress, err := s.GetUserProgress(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	got, exists := progress[4]
+	if !exists {
+		t.Fatal("progress for character 4 missing")
+	}
+	if got.Attempts != 3 || got.LastStroke != 2 {
+		t.Fatalf("attempts/lastStroke = %d/%d, want 3/2", got.Attempts, got.LastStroke)
+	}
+	if !approxEqual(got.AvgScore, 0.8) {
+		t.Fatalf("avgScore = %v, want 0.8", got.AvgScore)
+	}
+	// 近期得分 = 0.3×0.9 + 0.7×(0.3×0.5 + 0.7×1.0)
+	if !approxEqual(got.RecentScore, 0.865) {
+		t.Fatalf("recentScore = %v, want 0.865", got.RecentScore)
+	}
+	// 讀取時熟練度已依經過的時間些微衰減
+	if got.Mastery > 86.5+1e-9 || got.Mastery < 86.5-1e-3 {
+		t.Fatalf("mastery = %v, want 86.5", got.Mastery)
+	}
+	if !got.LastPracticedAt.Equal(at) {
+		t.Fatalf("lastPracticedAt = %v, want %v", got.LastPracticedAt, at)
+	}
+}
+
+func testProgressHistory(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "history")
+	user := mustCreateUser(t, s, org.ID, "ivan")
+
+	// 2026-03-02 為星期一；字元 1 得滿分後約 9.66 天衰減至門檻以下（3 月 12 日 01:46 UTC）
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	practice := []struct {
+		characterID int
+		score       float64
+		at          time.Time
+	}{
+		{1, 1.0, monday.Add(10 * time.Hour)},
+		{2, 0.5, monday.Add(11 * time.Hour)},
+		{2, 0.7, monday.Add(47*time.Hour + 30*time.Minute)},
+	}
+	for _, p := range practice {
+		record := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: p.characterID, Score: p.score, CreatedAt: p.at})
+		if err := storage.ApplyStrokeRecord(ctx, s, org.ID, *record); err != nil {
+			t.Fatalf("ApplyStrokeRecord: %v", err)
+		}
+	}
+
+	history := func(from time.Time, days int, interval models.HistoryInterval) []models.ProgressHistoryPoint {
+		t.Helper()
+		points, err := s.GetProgressHistory(ctx, org.ID, models.ProgressHistoryQuery{
+			UserID:   user.ID,
+			From:     from,
+			To:       from.AddDate(0, 0, days),
+			Interval: interval,
+		})
+		if err != nil {
+			t.Fatalf("GetProgressHistory: %v", err)
+		}
+		return points
+	}
+
+	daily := history(monday, 12, models.HistoryIntervalDay)
+	if len(daily) != 12 {
+		t.Fatalf("got %d daily points, want 12", len(daily))
+	}
+	want := []struct {
+		day, attempts, mastered int
+		avgScore                float64
+	}{
+		{0, 2, 1, 0.75},
+		{1, 1, 1, 0.7},
+		{2, 0, 1, 0},
+		{9, 0, 1, 0},
+		{10, 0, 0, 0},
+	}
+	for _, w := range want {
+		point := daily[w.day]
+		if !point.PeriodStart.Equal(monday.AddDate(0, 0, w.day)) || point.Attempts != w.attempts ||
+			!approxEqual(point.AvgScore, w.avgScore) || point.MasteredCharacters != w.mastered {
+			t.Errorf("day %d = %+v, want %d attempts averaging %v with %d mastered", w.day, point, w.attempts, w.avgScore, w.mastered)
+		}
+	}
+
+	// 以查詢的時區劃分日界線：台北時間 3 月 3 日 23:30 UTC 的練習屬於 3 月 4 日
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+	local := history(time.Date(2026, 3, 2, 0, 0, 0, 0, taipei), 3, models.HistoryIntervalDay)
+	if local[0].Attempts != 2 || local[1].Attempts != 0 || local[2].Attempts != 1 {
+		t.Errorf("attempts in UTC+8 = %d, %d, %d; want 2, 0, 1", local[0].Attempts, local[1].Attempts, local[2].Attempts)
+	}
+
+	// 查詢起點之前的熟練字元計入第一個區間
+	if later := history(monday.AddDate(0, 0, 5), 1, models.HistoryIntervalDay); later[0].Attempts != 0 || later[0].MasteredCharacters != 1 {
+		t.Errorf("day 5 = %+v, want no attempts with 1 mastered", later[0])
+	}
+
+	weekly := history(monday.AddDate(0, 0, 3), 14, models.HistoryIntervalWeek)
+	if len(weekly) != 3 || !weekly[0].PeriodStart.Equal(monday) {
+		t.Fatalf("weekly history = %+v, want 3 weeks from %v", weekly, monday)
+	}
+	if weekly[0].Attempts != 3 || weekly[0].MasteredCharacters != 1 || weekly[1].Attempts != 0 || weekly[1].MasteredCharacters != 0 {
+		t.Errorf("weekly history = %+v, want 3 attempts with 1 mastered, then none", weekly)
+	}
+}
+
+func testAssignmentDueProgress(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "assignments")
+	teacher := mustCreateUser(t, s, org.ID, "judy")
+	early := mustCreateUser(t, s, org.ID, "kim")
+	late := mustCreateUser(t, s, org.ID, "leo")
+
+	classroom, err := s.CreateClassroom(ctx, models.Classroom{OrgID: org.ID, Name: "2B", TeacherID: teacher.ID, EnrolmentCode: "DUE123"})
+	if err != nil {
+		t.Fatalf("CreateClassroom: %v", err)
+	}
+	for _, student := range []*models.User{early, late} {
+		if _, err := s.AddClassMember(ctx, classroom.ID, student.ID); err != nil {
+			t.Fatalf("AddClassMember: %v", err)
+		}
+	}
+	due := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	assignment, err := s.CreateAssignment(ctx, models.Assignment{ClassID: classroom.ID, Title: "一", CharacterIDs: []int{1}, DueAt: due, MasteryThreshold: models.MasteryThreshold})
+	if err != nil {
+		t.Fatalf("CreateAssignment: %v", err)
+	}
+
+	practise := func(user *models.User, score float64, at time.Time) {
+		t.Helper()
+		record := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Score: score, CreatedAt: at})
+		if err := storage.ApplyStrokeRecord(ctx, s, org.ID, *record); err != nil {
+			t.Fatalf("ApplyStrokeRecord: %v", err)
+		}
+	}
+	status := func(user *models.User, now time.Time) string {
+		t.Helper()
+		progress, err := s.GetUserProgress(ctx, org.ID, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserProgress: %v", err)
+		}
+		dueProgress, err := s.GetAssignmentDueProgress(ctx, org.ID, assignment.ID)
+		if err != nil {
+			t.Fatalf("GetAssignmentDueProgress: %v", err)
+		}
+		return assignment.StatusFor(user.ID, progress, dueProgress, now).Status
+	}
+
+	// 到期前完成的作業，之後的低分練習不會讓它變回逾期
+	practise(early, 0.9, due.Add(-time.Hour))
+	practise(late, 0.5, due.Add(-time.Hour))
+	if got := status(early, due.Add(time.Minute)); got != models.AssignmentCompleted {
+		t.Fatalf("status after completing before due = %q, want completed", got)
+	}
+	practise(early, 0.1, due.Add(time.Hour))
+	if got := status(early, due.Add(2*time.Hour)); got != models.AssignmentCompleted {
+		t.Errorf("status after practising badly past due = %q, want completed", got)
+	}
+
+	// 逾期的作業不會因到期後的練習而變為完成
+	for i := 0; i < 5; i++ {
+		practise(late, 1.0, due.Add(time.Duration(i+1)*time.Hour))
+	}
+	if got := status(late, due.Add(6*time.Hour)); got != models.AssignmentOverdue {
+		t.Errorf("status after practising past due = %q, want overdue", got)
+	}
+
+	// 每位學生只保存第一次的到期進度
+	for _, student := range []*models.User{early, late} {
+		if saved, err := s.HasAssignmentDueProgress(ctx, org.ID, assignment.ID, student.ID); err != nil || !saved {
+			t.Fatalf("HasAssignmentDueProgress(%s) = %v, %v; want true", student.Username, saved, err)
+		}
+	}
+	if saved, err := s.HasAssignmentDueProgress(ctx, org.ID, assignment.ID, teacher.ID); err != nil || saved {
+		t.Fatalf("HasAssignmentDueProgress for a user who never practised = %v, %v; want false", saved, err)
+	}
+	saved, err := s.SaveAssignmentDueProgress(ctx, assignment.ID, late.ID, models.UserProgress{1: {CharacterID: 1, Attempts: 1, RecentScore: 1}})
+	if err != nil || saved {
+		t.Fatalf("SaveAssignmentDueProgress again = %v, %v; want false", saved, err)
+	}
+
+	// 延後到期時間後，已保存的到期進度清除，以目前的進度評估
+	assignment.DueAt = due.Add(24 * time.Hour)
+	if _, err := s.UpdateAssignment(ctx, *assignment); err != nil {
+		t.Fatalf("UpdateAssignment: %v", err)
+	}
+	if dueProgress, err := s.GetAssignmentDueProgress(ctx, org.ID, assignment.ID); err != nil || len(dueProgress) != 0 {
+		t.Fatalf("due progress after moving the due date = %v, %v; want none", dueProgress, err)
+	}
+	if got := status(late, due.Add(6*time.Hour)); got != models.AssignmentCompleted {
+		t.Errorf("status after extending the due date = %q, want completed", got)
+	}
+}
+
+func testUnlockAchievementOnce(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "achievements")
+	user := mustCreateUser(t, s, org.ID, "ivan")
+	at := time.Now()
+	unlocked, err := s.UnlockAchievement(ctx, org.ID, user.ID, "strokes_100", at)
+	if err != nil || !unlocked {
+		t.Fatalf("first UnlockAchievement = %v, %v; want true", unlocked, err)
+	}
+	unlocked, err = s.UnlockAchievement(ctx, org.ID, user.ID, "strokes_100", at)
+	if err != nil || unlocked {
+		t.Fatalf("second UnlockAchievement = %v, %v; want false", unlocked, err)
+	}
+	if got, err := s.GetUserAchievements(ctx, org.ID, user.ID); err != nil || len(got) != 1 {
+		t.Fatalf("GetUserAchievements = %d achievements, %v; want 1", len(got), err)
+	}
+}
+
+func testNotFoundErrors(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	const missing = 999999
+	org := mustCreateOrganization(t, s, "missing")
+
+	checks := map[string]error{}
+	_, checks["GetOrganizationByID"] = s.GetOrganizationByID(ctx, missing)
+	_, checks["GetOrganizationBySlug"] = s.GetOrganizationBySlug(ctx, "no-such-org")
+	_, checks["GetUserByID"] = s.GetUserByID(ctx, org.ID, missing)
+	_, checks["GetUserByUsername"] = s.GetUserByUsername(ctx, org.ID, "nobody")
+	_, checks["UpdateUser"] = s.UpdateUser(ctx, models.User{ID: missing, OrgID: org.ID})
+	_, checks["GetCharacterByID"] = s.GetCharacterByID(ctx, org.ID, missing)
+	_, checks["GetDeckByID"] = s.GetDeckByID(ctx, org.ID, missing)
+	_, checks["GetClassroomByID"] = s.GetClassroomByID(ctx, org.ID, missing)
+	_, checks["GetClassroomByCode"] = s.GetClassroomByCode(ctx, org.ID, "NOPE")
+	_, checks["GetAssignmentByID"] = s.GetAssignmentByID(ctx, org.ID, missing)
+	_, checks["GetUserProgress"] = s.GetUserProgress(ctx, org.ID, missing)
+	_, checks["GetClassMembers"] = s.GetClassMembers(ctx, org.ID, missing)
+	_, checks["GetAssignmentsByClassID"] = s.GetAssignmentsByClassID(ctx, org.ID, missing)
+	_, checks["GetGuardianLinkByID"] = s.GetGuardianLinkByID(ctx, org.ID, missing)
+	checks["DeleteAssignment"] = s.DeleteAssignment(ctx, org.ID, missing)
+	_, checks["SaveAssignmentDueProgress"] = s.SaveAssignmentDueProgress(ctx, missing, missing, models.UserProgress{})
+	_, checks["GetAssignmentDueProgress"] = s.GetAssignmentDueProgress(ctx, org.ID, missing)
+	_, checks["HasAssignmentDueProgress"] = s.HasAssignmentDueProgress(ctx, org.ID, missing, missing)
+	checks["DeleteGuardianLink"] = s.DeleteGuardianLink(ctx, org.ID, missing)
+
+	for name, err := range checks {
+		if !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("%s = %v, want ErrNotFound", name, err)
+		}
+	}
+}
+
+func testTransactionCommits(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "commit")
+	user := mustCreateUser(t, s, org.ID, "judy")
+	err := s.RunInTx(ctx, func(tx storage.Storage) error {
+		record := mustCreateStrokeRecord(t, tx, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Score: 0.9, ClientID: "tx"})
+		return tx.UpdateUserProgress(ctx, org.ID, record.UserID, record.CharacterID, record.StrokeIndex, record.Score, record.CreatedAt)
+	})
+	if err != nil {
+		t.Fatalf("RunInTx: %v", err)
+	}
+
+	if _, err := s.GetStrokeRecordByClientID(ctx, org.ID, user.ID, "tx"); err != nil {
+		t.Fatalf("committed record missing: %v", err)
+	}
+	if progress, err := s.GetUserProgress(ctx, org.ID, user.ID); err != nil || progress[1].Attempts != 1 {
+		t.Fatalf("committed progress = %v, %v; want 1 attempt", progress, err)
+	}
+}
+
+func testTransactionRollsBack(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	org := mustCreateOrganization(t, s, "rollback")
+	user := mustCreateUser(t, s, org.ID, "dave")
+	before := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Score: 0.5})
+	if err := s.UpdateUserProgress(ctx, org.ID, user.ID, 1, 0, 0.5, before.CreatedAt); err != nil {
+		t.Fatalf("UpdateUserProgress: %v", err)
+	}
+
+	failure := errors.New("fail")
+	err := s.RunInTx(ctx, func(tx storage.Storage) error {
+		at := time.Now()
+		record := mustCreateStrokeRecord(t, tx, models.StrokeRecord{UserID: user.ID, CharacterID: 2, Score: 0.9, ClientID: "rolled-back", CreatedAt: at})
+		if err := tx.UpdateUserProgress(ctx, org.ID, user.ID, record.CharacterID, 0, record.Score, at); err != nil {
+			return err
+		}
+		if err := tx.UpdateUserProgress(ctx, org.ID, user.ID, 1, 0, record.Score, at); err != nil {
+			return err
+		}
+		if err := tx.RecordDailyActivity(ctx, org.ID, user.ID, record.CharacterID, at.Format("2006-01-02"), at); err != nil {
+			return err
+		}
+		if _, err := tx.UnlockAchievement(ctx, org.ID, user.ID, "strokes_100", at); err != nil {
+			return err
+		}
+		user.Email = "dave@example.com"
+		if _, err := tx.UpdateUser(ctx, *user); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("RunInTx = %v, want the error returned by fn", err)
+	}
+
+	records, err := s.GetStrokeRecordsByUserID(ctx, org.ID, user.ID)
+	if err != nil || len(records) != 1 || records[0].ID != before.ID {
+		t.Fatalf("records after rollback = %v, %v; want only record %d", records, err, before.ID)
+	}
+	if _, err := s.GetStrokeRecordByClientID(ctx, org.ID, user.ID, "rolled-back"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("GetStrokeRecordByClientID after rollback = %v, want ErrNotFound", err)
+	}
+	progress, err := s.GetUserProgress(ctx, org.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetUserProgress: %v", err)
+	}
+	if _, exists := progress[2]; exists || progress[1].Attempts != 1 {
+		t.Fatalf("progress after rollback = %+v, want only the committed attempt", progress)
+	}
+	if streak, err := s.GetStreak(ctx, org.ID, user.ID); err != nil || streak.Current != 0 {
+		t.Fatalf("streak after rollback = %+v, %v; want none", streak, err)
+	}
+	if achievements, err := s.GetUserAchievements(ctx, org.ID, user.ID); err != nil || len(achievements) != 0 {
+		t.Fatalf("achievements after rollback = %v, %v; want none", achievements, err)
+	}
+	if got, err := s.GetUserByID(ctx, org.ID, user.ID); err != nil || got.Email != "" {
+		t.Fatalf("user after rollback = %+v, %v; want unchanged", got, err)
+	}
+
+	// 回滾後的儲存仍可正常寫入，記錄ID持續遞增
+	after := mustCreateStrokeRecord(t, s, models.StrokeRecord{UserID: user.ID, CharacterID: 1, Score: 0.5, ClientID: "rolled-back"})
+	if after.ID <= before.ID {
+		t.Fatalf("record ID after rollback = %d, want greater than %d", after.ID, before.ID)
+	}
+}
+
+// approxEqual 比較浮點數是否在誤差範圍內相等
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}